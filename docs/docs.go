@@ -19,6 +19,147 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/backfills": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "list backfill jobs, sorted by start time; admin only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "backfill"
+                ],
+                "summary": "list backfills",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/messages.BackfillJob"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "starts an asynchronous import of historic camunda incidents; incidents already stored are skipped; admin only; missing open incidents are handled like incidents reported by camunda (stop, restart, notify); running jobs without progress for 15 minutes are reported as failed; jobs are removed after incident_backfill_job_retention since their last progress",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "backfill"
+                ],
+                "summary": "start backfill",
+                "parameters": [
+                    {
+                        "description": "Backfill-Request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/messages.BackfillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/messages.BackfillJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/backfills/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "get backfill job with its progress; admin only",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "backfill"
+                ],
+                "summary": "get backfill",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "backfill id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/messages.BackfillJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
         "/incidents": {
             "get": {
                 "security": [
@@ -43,10 +184,16 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "offset to be used in combination with limit, default 0",
+                        "description": "offset to be used in combination with limit, default 0; ignored if cursor is set",
                         "name": "offset",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "opaque token from the X-Next-Cursor header of the previous page; must be used with the same sort field and direction",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "default id.asc, sortable by id, external_task_id, process_instance_id, process_definition_id, time",
@@ -70,6 +217,30 @@ const docTemplate = `{
                         "description": "filter by external_task_id",
                         "name": "external_task_id",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by time; only incidents at or after this RFC3339 timestamp (e.g. 2025-01-01T00:00:00Z)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by time; only incidents at or before this RFC3339 timestamp (e.g. 2025-01-02T00:00:00Z)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "full-text search over error_message, deployment_name and business_key",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by status (open, acknowledged, resolved); other values are rejected",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -80,6 +251,12 @@ const docTemplate = `{
                             "items": {
                                 "$ref": "#/definitions/messages.IncidentMessage"
                             }
+                        },
+                        "headers": {
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "cursor to request the next page; missing if the page is not full"
+                            }
                         }
                     },
                     "400": {
@@ -146,35 +323,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/incidents/{id}": {
+        "/incidents/count": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "get incident",
+                "description": "count incidents matching the same filters as GET /incidents",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "incidents"
                 ],
-                "summary": "get incident",
+                "summary": "count incidents",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Incident Id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "filter by process_definition_id",
+                        "name": "process_definition_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by process_instance_id",
+                        "name": "process_instance_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by external_task_id",
+                        "name": "external_task_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by time; only incidents at or after this RFC3339 timestamp (e.g. 2025-01-01T00:00:00Z)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by time; only incidents at or before this RFC3339 timestamp (e.g. 2025-01-02T00:00:00Z)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "full-text search over error_message, deployment_name and business_key",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by status (open, acknowledged, resolved); other values are rejected",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/messages.IncidentMessage"
+                            "type": "integer"
                         }
                     },
                     "400": {
@@ -195,35 +407,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/on-incident-handler": {
-            "put": {
+        "/incidents/{id}": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "set on incident handler, user must be admin",
+                "description": "get incident",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "incidents"
                 ],
-                "summary": "set on incident handler",
+                "summary": "get incident",
                 "parameters": [
                     {
-                        "description": "Incident-Handler",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/messages.OnIncident"
-                        }
+                        "type": "string",
+                        "description": "Incident Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "if true, large details like the stack_trace are included; default false",
+                        "name": "details",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/messages.IncidentMessage"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -243,25 +462,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/process-definitions/{id}": {
-            "delete": {
+        "/incidents/{id}/comments": {
+            "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "delete incidents by process-definition id, user must be admin",
+                "description": "list comments of an incident of the requesting user, sorted by time",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "incidents"
                 ],
-                "summary": "delete incidents by process-definition id",
+                "summary": "list incident comments",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "process-definition id",
+                        "description": "Incident Id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -269,7 +488,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/messages.IncidentComment"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -287,35 +512,45 @@ const docTemplate = `{
                         "description": "Internal Server Error"
                     }
                 }
-            }
-        },
-        "/process-instances/{id}": {
-            "delete": {
+            },
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "delete incidents by process-instance id, user must be admin",
+                "description": "add a comment to an incident of the requesting user; only the text field of the body is used",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "incidents"
                 ],
-                "summary": "delete incidents by process-instance id",
+                "summary": "add incident comment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "process-instance id",
+                        "description": "Incident Id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/messages.IncidentComment"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/messages.IncidentComment"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -334,15 +569,812 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "messages.IncidentMessage": {
-            "type": "object",
-            "properties": {
-                "deployment_name": {
-                    "type": "string"
-                },
+        },
+        "/incidents/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "acknowledge, resolve or reopen an incident of the requesting user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "set incident status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "status: open, acknowledged or resolved",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/messages.IncidentStatusUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/notification-channels": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "list notification channels, sorted by id; non admin users receive only their own channels",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-channels"
+                ],
+                "summary": "list notification channels",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "limits size of result; default 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset to be used in combination with limit, default 0",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/messages.NotificationChannel"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "creates the notification channel if the id is empty, replaces it otherwise; types are webhook (hmac-sha256 signed with secret in X-Incident-Signature-256), email and chat (slack/mattermost incoming webhook); webhook and chat urls may not resolve to loopback, private or link-local addresses, unless the host is allowed by notification_channel_allowed_hosts; the secret is never returned and kept if omitted on update; non admin users may only set channels for themselves and process-definitions they own",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-channels"
+                ],
+                "summary": "set notification channel",
+                "parameters": [
+                    {
+                        "description": "Notification-Channel",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/messages.NotificationChannel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/messages.NotificationChannel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/notification-channels/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "get notification channel; non admin users may only access their own channels",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-channels"
+                ],
+                "summary": "get notification channel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "channel id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/messages.NotificationChannel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "delete notification channel; pending notifications of the channel are dropped; non admin users may only delete their own channels",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notification-channels"
+                ],
+                "summary": "delete notification channel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "channel id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/on-incident-handler": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "list on incident handlers, sorted by process_definition_id; non admin users receive only their own handlers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "list on incident handlers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "limits size of result; default 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset to be used in combination with limit, default 0",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/messages.OnIncident"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "set on incident handler; admins may set handlers for every process-definition and keep the owner of an existing handler by omitting tenant_id, other users only for process-definitions they own",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "set on incident handler",
+                "parameters": [
+                    {
+                        "description": "Incident-Handler",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/messages.OnIncident"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/on-incident-handler/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "get on incident handler of a process-definition; non admin users must own the process-definition",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "get on incident handler",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "process-definition id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/messages.OnIncident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "delete on incident handler of a process-definition, incidents are not deleted; non admin users must own the process-definition",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "delete on incident handler",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "process-definition id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/process-definitions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "delete incidents by process-definition id, user must be admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "delete incidents by process-definition id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "process-definition id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/process-instances/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "delete incidents by process-instance id, user must be admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "delete incidents by process-instance id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "process-instance id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        },
+        "/tenant-settings/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "get notification settings of a tenant; non admin users may only read their own settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tenant-settings"
+                ],
+                "summary": "get tenant settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "tenant id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/messages.TenantSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "set notification settings of a tenant; templates use go text/template syntax and may be set for incident, restart_notice, restart_failed, restart_disabled and digest; incidents exceeding the rate_limit or of tenants with digest_interval are notified as digest; severity_rules set the severity of new incidents (first match wins) and quiet_hours.routing suppresses or delays notifications by severity; non admin users may only set their own settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tenant-settings"
+                ],
+                "summary": "set tenant settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "tenant id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tenant-Settings",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/messages.TenantSettings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "delete notification settings of a tenant, default templates and locale are used afterwards; non admin users may only delete their own settings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tenant-settings"
+                ],
+                "summary": "delete tenant settings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "tenant id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "401": {
+                        "description": "Unauthorized"
+                    },
+                    "403": {
+                        "description": "Forbidden"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "messages.BackfillJob": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "error by shard; \"job\" for errors of the whole job",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "finished": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inserted": {
+                    "description": "count of incidents stored by this job; open incidents are handled like incidents reported by camunda",
+                    "type": "integer"
+                },
+                "invalid": {
+                    "description": "count of incidents that could not be stored because of missing data, e.g. without tenant",
+                    "type": "integer"
+                },
+                "read": {
+                    "description": "count of historic incidents read from camunda",
+                    "type": "integer"
+                },
+                "request": {
+                    "$ref": "#/definitions/messages.BackfillRequest"
+                },
+                "skipped": {
+                    "description": "count of incidents that were already stored",
+                    "type": "integer"
+                },
+                "started": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated": {
+                    "description": "time of the last stored progress; running jobs without progress for a long time are marked as failed",
+                    "type": "string"
+                }
+            }
+        },
+        "messages.BackfillRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "optional; incidents created after this time",
+                    "type": "string"
+                },
+                "shards": {
+                    "description": "optional; defaults to all shards",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "until": {
+                    "description": "optional; incidents created before this time",
+                    "type": "string"
+                }
+            }
+        },
+        "messages.IncidentComment": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "incident_id": {
+                    "type": "string"
+                },
+                "process_definition_id": {
+                    "type": "string"
+                },
+                "process_instance_id": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "messages.IncidentMessage": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "string"
+                },
+                "business_key": {
+                    "type": "string"
+                },
+                "cause_incident_id": {
+                    "type": "string"
+                },
+                "configuration": {
+                    "description": "job id for failedJob, external task id for failedExternalTask",
+                    "type": "string"
+                },
+                "deployment_name": {
+                    "type": "string"
+                },
                 "error_message": {
                     "type": "string"
                 },
@@ -352,16 +1384,52 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "incident_type": {
+                    "description": "metadata of incidents loaded from camunda",
+                    "type": "string"
+                },
+                "job_definition_id": {
+                    "type": "string"
+                },
+                "last_seen": {
+                    "description": "time of the last deduplicated report",
+                    "type": "string"
+                },
                 "msg_version": {
                     "description": "from version 3 onward will be set in KafkaIncidentsCommand and be copied to this field",
                     "type": "integer"
                 },
+                "occurrence_count": {
+                    "description": "count of reports of this incident including deduplicated ones; empty = 1",
+                    "type": "integer"
+                },
                 "process_definition_id": {
                     "type": "string"
                 },
                 "process_instance_id": {
                     "type": "string"
                 },
+                "root_cause_incident_id": {
+                    "type": "string"
+                },
+                "severity": {
+                    "description": "one of the Severity constants; derived from the severity rules of the tenant",
+                    "type": "string"
+                },
+                "stack_trace": {
+                    "description": "only returned by GET /incidents/{id}?details=true",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "empty status is equivalent to IncidentStatusOpen",
+                    "type": "string"
+                },
+                "status_changed_at": {
+                    "type": "string"
+                },
+                "status_changed_by": {
+                    "type": "string"
+                },
                 "tenant_id": {
                     "type": "string"
                 },
@@ -373,9 +1441,83 @@ const docTemplate = `{
                 }
             }
         },
+        "messages.IncidentStatusUpdate": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "messages.NotificationChannel": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "process_definition_id": {
+                    "type": "string"
+                },
+                "recipients": {
+                    "description": "email",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "webhook; used to sign the payload with hmac-sha256; never returned by the api",
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "webhook and chat",
+                    "type": "string"
+                }
+            }
+        },
+        "messages.NotificationTemplate": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
         "messages.OnIncident": {
             "type": "object",
             "properties": {
+                "deduplication_window": {
+                    "description": "duration in which further incidents of the same process-instance are only counted; overrides the global config; \"0s\" disables deduplication",
+                    "type": "string"
+                },
+                "digest": {
+                    "description": "incidents are collected in the digest of the tenant instead of being notified immediately",
+                    "type": "boolean"
+                },
+                "keep_alive": {
+                    "description": "the process-instance is not stopped; the incident is only stored and notified",
+                    "type": "boolean"
+                },
+                "max_restart_backoff": {
+                    "description": "upper limit for the restart delay; defaults to 1h",
+                    "type": "string"
+                },
+                "max_restarts": {
+                    "description": "max count of restarts within restart_window; 0 = unlimited; restart is disabled if exceeded",
+                    "type": "integer"
+                },
                 "notify": {
                     "type": "boolean"
                 },
@@ -384,6 +1526,120 @@ const docTemplate = `{
                 },
                 "restart": {
                     "type": "boolean"
+                },
+                "restart_backoff": {
+                    "description": "delay of the first restart within restart_window, doubled for every further restart; empty = no delay",
+                    "type": "string"
+                },
+                "restart_count": {
+                    "description": "restart state, managed by the service",
+                    "type": "integer"
+                },
+                "restart_mode": {
+                    "description": "one of the OnIncidentRestartMode constants; defaults to OnIncidentRestartModeProcess",
+                    "type": "string"
+                },
+                "restart_retries": {
+                    "description": "retries set on the failed job or external-task in OnIncidentRestartModeRetry; defaults to 1",
+                    "type": "integer"
+                },
+                "restart_window": {
+                    "description": "duration like \"1h\"; defaults to 1h",
+                    "type": "string"
+                },
+                "restart_window_start": {
+                    "type": "string"
+                },
+                "restart_with_start_parameters": {
+                    "description": "restart with the start-parameters of the failed process-instance",
+                    "type": "boolean"
+                },
+                "tenant_id": {
+                    "description": "set to the owning user if the handler is not created by an admin",
+                    "type": "string"
+                }
+            }
+        },
+        "messages.QuietHours": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "local time like \"07:00\"; may be before start to span midnight",
+                    "type": "string"
+                },
+                "routing": {
+                    "description": "QuietHoursAction by severity; severities without action are notified immediately",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "start": {
+                    "description": "local time like \"22:00\"",
+                    "type": "string"
+                },
+                "time_zone": {
+                    "description": "IANA time zone like \"Europe/Berlin\"; defaults to UTC",
+                    "type": "string"
+                }
+            }
+        },
+        "messages.SeverityRule": {
+            "type": "object",
+            "properties": {
+                "error_message_pattern": {
+                    "description": "regular expression",
+                    "type": "string"
+                },
+                "incident_type": {
+                    "type": "string"
+                },
+                "process_definition_id": {
+                    "type": "string"
+                },
+                "severity": {
+                    "type": "string"
+                }
+            }
+        },
+        "messages.TenantSettings": {
+            "type": "object",
+            "properties": {
+                "digest_interval": {
+                    "description": "if set, all incidents are collected and notified as one digest per interval (e.g. \"15m\"); otherwise only incidents of handlers with digest and incidents exceeding the rate limit",
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "locale of notifications; defaults to the configured notification_default_locale",
+                    "type": "string"
+                },
+                "quiet_hours": {
+                    "$ref": "#/definitions/messages.QuietHours"
+                },
+                "rate_limit": {
+                    "description": "max count of immediate incident notifications within rate_limit_window; further incidents are collected in the digest; 0 = configured default; -1 = unlimited",
+                    "type": "integer"
+                },
+                "rate_limit_window": {
+                    "description": "defaults to the configured notification_rate_limit_window",
+                    "type": "string"
+                },
+                "severity_rules": {
+                    "description": "the first matching rule sets the severity of an incident; incidents without matching rule get the configured incident_default_severity",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/messages.SeverityRule"
+                    }
+                },
+                "templates": {
+                    "description": "overrides the templates of the locale by template name (incident, restart_notice, restart_failed, restart_disabled, digest)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/messages.NotificationTemplate"
+                    }
+                },
+                "tenant_id": {
+                    "type": "string"
                 }
             }
         }
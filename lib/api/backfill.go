@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"github.com/SENERGY-Platform/process-incident-api/lib/api/util"
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+func init() {
+	endpoints = append(endpoints, &BackfillEndpoints{})
+}
+
+type BackfillEndpoints struct{}
+
+// StartBackfill godoc
+// @Summary      start backfill
+// @Description  starts an asynchronous import of historic camunda incidents; incidents already stored are skipped; admin only; missing open incidents are handled like incidents reported by camunda (stop, restart, notify); running jobs without progress for 15 minutes are reported as failed; jobs are removed after incident_backfill_job_retention since their last progress
+// @Tags         backfill
+// @Produce      json
+// @Security Bearer
+// @Param        message body messages.BackfillRequest true "Backfill-Request"
+// @Success      200 {object} messages.BackfillJob
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /backfills [POST]
+func (this *BackfillEndpoints) StartBackfill(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("POST /backfills", func(writer http.ResponseWriter, request *http.Request) {
+		backfillRequest := messages.BackfillRequest{}
+		err := json.NewDecoder(request.Body).Decode(&backfillRequest)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		job, err, code := ctrl.StartBackfill(util.GetAuthToken(request), backfillRequest)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(job)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// GetBackfill godoc
+// @Summary      get backfill
+// @Description  get backfill job with its progress; admin only
+// @Tags         backfill
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "backfill id"
+// @Success      200 {object} messages.BackfillJob
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /backfills/{id} [GET]
+func (this *BackfillEndpoints) GetBackfill(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("GET /backfills/{id}", func(writer http.ResponseWriter, request *http.Request) {
+		job, err, code := ctrl.GetBackfill(util.GetAuthToken(request), request.PathValue("id"))
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(job)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// ListBackfills godoc
+// @Summary      list backfills
+// @Description  list backfill jobs, sorted by start time; admin only
+// @Tags         backfill
+// @Produce      json
+// @Security Bearer
+// @Success      200 {array} messages.BackfillJob
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /backfills [GET]
+func (this *BackfillEndpoints) ListBackfills(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("GET /backfills", func(writer http.ResponseWriter, request *http.Request) {
+		jobs, err, code := ctrl.ListBackfills(util.GetAuthToken(request))
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		if jobs == nil {
+			jobs = []messages.BackfillJob{} //ensure json is '[]' and not 'null'
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(jobs)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"github.com/SENERGY-Platform/process-incident-api/lib/api/util"
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+func init() {
+	endpoints = append(endpoints, &ChannelEndpoints{})
+}
+
+type ChannelEndpoints struct{}
+
+// SetNotificationChannel godoc
+// @Summary      set notification channel
+// @Description  creates the notification channel if the id is empty, replaces it otherwise; types are webhook (hmac-sha256 signed with secret in X-Incident-Signature-256), email and chat (slack/mattermost incoming webhook); webhook and chat urls may not resolve to loopback, private or link-local addresses, unless the host is allowed by notification_channel_allowed_hosts; the secret is never returned and kept if omitted on update; non admin users may only set channels for themselves and process-definitions they own
+// @Tags         notification-channels
+// @Produce      json
+// @Security Bearer
+// @Param        message body messages.NotificationChannel true "Notification-Channel"
+// @Success      200 {object} messages.NotificationChannel
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /notification-channels [PUT]
+func (this *ChannelEndpoints) SetNotificationChannel(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("PUT /notification-channels", func(writer http.ResponseWriter, request *http.Request) {
+		channel := messages.NotificationChannel{}
+		err := json.NewDecoder(request.Body).Decode(&channel)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err, code := ctrl.SetNotificationChannel(util.GetAuthToken(request), channel)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// GetNotificationChannel godoc
+// @Summary      get notification channel
+// @Description  get notification channel; non admin users may only access their own channels
+// @Tags         notification-channels
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "channel id"
+// @Success      200 {object} messages.NotificationChannel
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /notification-channels/{id} [GET]
+func (this *ChannelEndpoints) GetNotificationChannel(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("GET /notification-channels/{id}", func(writer http.ResponseWriter, request *http.Request) {
+		channel, err, code := ctrl.GetNotificationChannel(util.GetAuthToken(request), request.PathValue("id"))
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(channel)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// ListNotificationChannels godoc
+// @Summary      list notification channels
+// @Description  list notification channels, sorted by id; non admin users receive only their own channels
+// @Tags         notification-channels
+// @Produce      json
+// @Security Bearer
+// @Param        limit query integer false "limits size of result; default 100"
+// @Param        offset query integer false "offset to be used in combination with limit, default 0"
+// @Success      200 {array} messages.NotificationChannel
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /notification-channels [GET]
+func (this *ChannelEndpoints) ListNotificationChannels(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("GET /notification-channels", func(writer http.ResponseWriter, request *http.Request) {
+		limit, err := util.ParseLimit(request.URL.Query().Get("limit"))
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offset, err := util.ParseOffset(request.URL.Query().Get("offset"))
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		channels, err, code := ctrl.ListNotificationChannels(util.GetAuthToken(request), limit, offset)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		if channels == nil {
+			channels = []messages.NotificationChannel{} //ensure json is '[]' and not 'null'
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(channels)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// DeleteNotificationChannel godoc
+// @Summary      delete notification channel
+// @Description  delete notification channel; pending notifications of the channel are dropped; non admin users may only delete their own channels
+// @Tags         notification-channels
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "channel id"
+// @Success      200
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /notification-channels/{id} [DELETE]
+func (this *ChannelEndpoints) DeleteNotificationChannel(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("DELETE /notification-channels/{id}", func(writer http.ResponseWriter, request *http.Request) {
+		err, code := ctrl.DeleteNotificationChannel(util.GetAuthToken(request), request.PathValue("id"))
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	})
+}
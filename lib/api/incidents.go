@@ -40,6 +40,7 @@ type IncidentsEndpoints struct{}
 // @Produce      json
 // @Security Bearer
 // @Param        id path string true "Incident Id"
+// @Param        details query bool false "if true, large details like the stack_trace are included; default false"
 // @Success      200 {object} messages.IncidentMessage
 // @Failure      400
 // @Failure      401
@@ -50,7 +51,18 @@ type IncidentsEndpoints struct{}
 func (this *IncidentsEndpoints) GetIncident(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
 	router.HandleFunc("GET /incidents/{id}", func(writer http.ResponseWriter, request *http.Request) {
 		id := request.PathValue("id")
-		incident, err, code := ctrl.GetIncident(util.GetAuthToken(request), id)
+		details, err := util.ParseBool(request.URL.Query().Get("details"), "details")
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var incident messages.IncidentMessage
+		var code int
+		if details {
+			incident, err, code = ctrl.GetIncidentDetails(util.GetAuthToken(request), id)
+		} else {
+			incident, err, code = ctrl.GetIncident(util.GetAuthToken(request), id)
+		}
 		if err != nil {
 			http.Error(writer, err.Error(), code)
 			return
@@ -71,12 +83,18 @@ func (this *IncidentsEndpoints) GetIncident(config configuration.Config, ctrl in
 // @Produce      json
 // @Security Bearer
 // @Param        limit query integer false "limits size of result; default 100"
-// @Param        offset query integer false "offset to be used in combination with limit, default 0"
+// @Param        offset query integer false "offset to be used in combination with limit, default 0; ignored if cursor is set"
+// @Param        cursor query string false "opaque token from the X-Next-Cursor header of the previous page; must be used with the same sort field and direction"
 // @Param        sort query string false "default id.asc, sortable by id, external_task_id, process_instance_id, process_definition_id, time"
 // @Param        process_definition_id query string false "filter by process_definition_id"
 // @Param        process_instance_id query string false "filter by process_instance_id"
 // @Param        external_task_id query string false "filter by external_task_id"
+// @Param        from query string false "filter by time; only incidents at or after this RFC3339 timestamp (e.g. 2025-01-01T00:00:00Z)"
+// @Param        until query string false "filter by time; only incidents at or before this RFC3339 timestamp (e.g. 2025-01-02T00:00:00Z)"
+// @Param        search query string false "full-text search over error_message, deployment_name and business_key"
+// @Param        status query string false "filter by status (open, acknowledged, resolved); other values are rejected"
 // @Success      200 {array}  messages.IncidentMessage
+// @Header       200 {string} X-Next-Cursor "cursor to request the next page; missing if the page is not full"
 // @Failure      400
 // @Failure      401
 // @Failure      403
@@ -85,10 +103,11 @@ func (this *IncidentsEndpoints) GetIncident(config configuration.Config, ctrl in
 // @Router       /incidents [GET]
 func (this *IncidentsEndpoints) ListIncidents(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
 	router.HandleFunc("GET /incidents", func(writer http.ResponseWriter, request *http.Request) {
-		processDefinitionId := request.URL.Query().Get("process_definition_id")
-		processInstanceId := request.URL.Query().Get("process_instance_id")
-		taskId := request.URL.Query().Get("external_task_id")
-
+		filter, err := parseIncidentFilter(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
 		limit, err := util.ParseLimit(request.URL.Query().Get("limit"))
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
@@ -105,7 +124,14 @@ func (this *IncidentsEndpoints) ListIncidents(config configuration.Config, ctrl
 			return
 		}
 
-		incidents, err, code := ctrl.FindIncidents(util.GetAuthToken(request), taskId, processDefinitionId, processInstanceId, limit, offset, sortField, sortAsc)
+		incidents, nextCursor, err, code := ctrl.FindIncidents(util.GetAuthToken(request), messages.FindIncidentsOptions{
+			IncidentFilter: filter,
+			Cursor:         request.URL.Query().Get("cursor"),
+			Limit:          limit,
+			Offset:         offset,
+			SortBy:         sortField,
+			Asc:            sortAsc,
+		})
 		if err != nil {
 			http.Error(writer, err.Error(), code)
 			return
@@ -113,6 +139,9 @@ func (this *IncidentsEndpoints) ListIncidents(config configuration.Config, ctrl
 		if incidents == nil {
 			incidents = []messages.IncidentMessage{} //ensure json is '[]' and not 'null'
 		}
+		if nextCursor != "" {
+			writer.Header().Set("X-Next-Cursor", nextCursor)
+		}
 		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
 		err = json.NewEncoder(writer).Encode(incidents)
 		if err != nil {
@@ -122,6 +151,68 @@ func (this *IncidentsEndpoints) ListIncidents(config configuration.Config, ctrl
 	})
 }
 
+// CountIncidents godoc
+// @Summary      count incidents
+// @Description  count incidents matching the same filters as GET /incidents
+// @Tags         incidents
+// @Produce      json
+// @Security Bearer
+// @Param        process_definition_id query string false "filter by process_definition_id"
+// @Param        process_instance_id query string false "filter by process_instance_id"
+// @Param        external_task_id query string false "filter by external_task_id"
+// @Param        from query string false "filter by time; only incidents at or after this RFC3339 timestamp (e.g. 2025-01-01T00:00:00Z)"
+// @Param        until query string false "filter by time; only incidents at or before this RFC3339 timestamp (e.g. 2025-01-02T00:00:00Z)"
+// @Param        search query string false "full-text search over error_message, deployment_name and business_key"
+// @Param        status query string false "filter by status (open, acknowledged, resolved); other values are rejected"
+// @Success      200 {integer} int64
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /incidents/count [GET]
+func (this *IncidentsEndpoints) CountIncidents(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("GET /incidents/count", func(writer http.ResponseWriter, request *http.Request) {
+		filter, err := parseIncidentFilter(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		count, err, code := ctrl.CountIncidents(util.GetAuthToken(request), filter)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(count)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// parseIncidentFilter reads the filter query parameters shared by GET /incidents and GET /incidents/count
+func parseIncidentFilter(request *http.Request) (filter messages.IncidentFilter, err error) {
+	query := request.URL.Query()
+	filter = messages.IncidentFilter{
+		ExternalTaskId:      query.Get("external_task_id"),
+		ProcessDefinitionId: query.Get("process_definition_id"),
+		ProcessInstanceId:   query.Get("process_instance_id"),
+		Search:              query.Get("search"),
+		Status:              query.Get("status"),
+	}
+	filter.From, err = util.ParseTime(query.Get("from"), "from")
+	if err != nil {
+		return filter, err
+	}
+	filter.Until, err = util.ParseTime(query.Get("until"), "until")
+	if err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
 // CreateIncident godoc
 // @Summary      create incident
 // @Description  create incident, user must be admin
@@ -153,9 +244,114 @@ func (this *IncidentsEndpoints) CreateIncident(config configuration.Config, ctrl
 	})
 }
 
+// SetIncidentStatus godoc
+// @Summary      set incident status
+// @Description  acknowledge, resolve or reopen an incident of the requesting user
+// @Tags         incidents
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "Incident Id"
+// @Param        message body messages.IncidentStatusUpdate true "status: open, acknowledged or resolved"
+// @Success      200
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /incidents/{id}/status [PUT]
+func (this *IncidentsEndpoints) SetIncidentStatus(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("PUT /incidents/{id}/status", func(writer http.ResponseWriter, request *http.Request) {
+		id := request.PathValue("id")
+		update := messages.IncidentStatusUpdate{}
+		err := json.NewDecoder(request.Body).Decode(&update)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err, code := ctrl.SetIncidentStatus(util.GetAuthToken(request), id, update.Status)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	})
+}
+
+// AddIncidentComment godoc
+// @Summary      add incident comment
+// @Description  add a comment to an incident of the requesting user; only the text field of the body is used
+// @Tags         incidents
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "Incident Id"
+// @Param        message body messages.IncidentComment true "Comment"
+// @Success      200 {object} messages.IncidentComment
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /incidents/{id}/comments [POST]
+func (this *IncidentsEndpoints) AddIncidentComment(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("POST /incidents/{id}/comments", func(writer http.ResponseWriter, request *http.Request) {
+		id := request.PathValue("id")
+		comment := messages.IncidentComment{}
+		err := json.NewDecoder(request.Body).Decode(&comment)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err, code := ctrl.AddIncidentComment(util.GetAuthToken(request), id, comment)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(result)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// ListIncidentComments godoc
+// @Summary      list incident comments
+// @Description  list comments of an incident of the requesting user, sorted by time
+// @Tags         incidents
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "Incident Id"
+// @Success      200 {array} messages.IncidentComment
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /incidents/{id}/comments [GET]
+func (this *IncidentsEndpoints) ListIncidentComments(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("GET /incidents/{id}/comments", func(writer http.ResponseWriter, request *http.Request) {
+		id := request.PathValue("id")
+		comments, err, code := ctrl.ListIncidentComments(util.GetAuthToken(request), id)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		if comments == nil {
+			comments = []messages.IncidentComment{} //ensure json is '[]' and not 'null'
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(comments)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
 // SetIncidentHandler godoc
 // @Summary      set on incident handler
-// @Description  set on incident handler, user must be admin
+// @Description  set on incident handler; admins may set handlers for every process-definition and keep the owner of an existing handler by omitting tenant_id, other users only for process-definitions they own
 // @Tags         incidents
 // @Produce      json
 // @Security Bearer
@@ -184,6 +380,107 @@ func (this *IncidentsEndpoints) SetIncidentHandler(config configuration.Config,
 	})
 }
 
+// GetIncidentHandler godoc
+// @Summary      get on incident handler
+// @Description  get on incident handler of a process-definition; non admin users must own the process-definition
+// @Tags         incidents
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "process-definition id"
+// @Success      200 {object} messages.OnIncident
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /on-incident-handler/{id} [GET]
+func (this *IncidentsEndpoints) GetIncidentHandler(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("GET /on-incident-handler/{id}", func(writer http.ResponseWriter, request *http.Request) {
+		id := request.PathValue("id")
+		handler, err, code := ctrl.GetOnIncidentHandler(util.GetAuthToken(request), id)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(handler)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// ListIncidentHandlers godoc
+// @Summary      list on incident handlers
+// @Description  list on incident handlers, sorted by process_definition_id; non admin users receive only their own handlers
+// @Tags         incidents
+// @Produce      json
+// @Security Bearer
+// @Param        limit query integer false "limits size of result; default 100"
+// @Param        offset query integer false "offset to be used in combination with limit, default 0"
+// @Success      200 {array} messages.OnIncident
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /on-incident-handler [GET]
+func (this *IncidentsEndpoints) ListIncidentHandlers(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("GET /on-incident-handler", func(writer http.ResponseWriter, request *http.Request) {
+		limit, err := util.ParseLimit(request.URL.Query().Get("limit"))
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offset, err := util.ParseOffset(request.URL.Query().Get("offset"))
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		handlers, err, code := ctrl.ListOnIncidentHandlers(util.GetAuthToken(request), limit, offset)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		if handlers == nil {
+			handlers = []messages.OnIncident{} //ensure json is '[]' and not 'null'
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(handlers)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// DeleteIncidentHandler godoc
+// @Summary      delete on incident handler
+// @Description  delete on incident handler of a process-definition, incidents are not deleted; non admin users must own the process-definition
+// @Tags         incidents
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "process-definition id"
+// @Success      200
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /on-incident-handler/{id} [DELETE]
+func (this *IncidentsEndpoints) DeleteIncidentHandler(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("DELETE /on-incident-handler/{id}", func(writer http.ResponseWriter, request *http.Request) {
+		id := request.PathValue("id")
+		err, code := ctrl.DeleteOnIncidentHandler(util.GetAuthToken(request), id)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	})
+}
+
 // DeleteIncidentByProcessDefinitionId godoc
 // @Summary      delete incidents by process-definition id
 // @Description  delete incidents by process-definition id, user must be admin
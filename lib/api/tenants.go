@@ -0,0 +1,122 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"github.com/SENERGY-Platform/process-incident-api/lib/api/util"
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+func init() {
+	endpoints = append(endpoints, &TenantSettingsEndpoints{})
+}
+
+type TenantSettingsEndpoints struct{}
+
+// SetTenantSettings godoc
+// @Summary      set tenant settings
+// @Description  set notification settings of a tenant; templates use go text/template syntax and may be set for incident, restart_notice, restart_failed, restart_disabled and digest; incidents exceeding the rate_limit or of tenants with digest_interval are notified as digest; severity_rules set the severity of new incidents (first match wins) and quiet_hours.routing suppresses or delays notifications by severity; non admin users may only set their own settings
+// @Tags         tenant-settings
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "tenant id"
+// @Param        message body messages.TenantSettings true "Tenant-Settings"
+// @Success      200
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /tenant-settings/{id} [PUT]
+func (this *TenantSettingsEndpoints) SetTenantSettings(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("PUT /tenant-settings/{id}", func(writer http.ResponseWriter, request *http.Request) {
+		settings := messages.TenantSettings{}
+		err := json.NewDecoder(request.Body).Decode(&settings)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		settings.TenantId = request.PathValue("id")
+		err, code := ctrl.SetTenantSettings(util.GetAuthToken(request), settings)
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	})
+}
+
+// GetTenantSettings godoc
+// @Summary      get tenant settings
+// @Description  get notification settings of a tenant; non admin users may only read their own settings
+// @Tags         tenant-settings
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "tenant id"
+// @Success      200 {object} messages.TenantSettings
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /tenant-settings/{id} [GET]
+func (this *TenantSettingsEndpoints) GetTenantSettings(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("GET /tenant-settings/{id}", func(writer http.ResponseWriter, request *http.Request) {
+		settings, err, code := ctrl.GetTenantSettings(util.GetAuthToken(request), request.PathValue("id"))
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		err = json.NewEncoder(writer).Encode(settings)
+		if err != nil {
+			debug.PrintStack()
+			log.Println("ERROR: ", err)
+		}
+	})
+}
+
+// DeleteTenantSettings godoc
+// @Summary      delete tenant settings
+// @Description  delete notification settings of a tenant, default templates and locale are used afterwards; non admin users may only delete their own settings
+// @Tags         tenant-settings
+// @Produce      json
+// @Security Bearer
+// @Param        id path string true "tenant id"
+// @Success      200
+// @Failure      400
+// @Failure      401
+// @Failure      403
+// @Failure      404
+// @Failure      500
+// @Router       /tenant-settings/{id} [DELETE]
+func (this *TenantSettingsEndpoints) DeleteTenantSettings(config configuration.Config, ctrl interfaces.Controller, router *http.ServeMux) {
+	router.HandleFunc("DELETE /tenant-settings/{id}", func(writer http.ResponseWriter, request *http.Request) {
+		err, code := ctrl.DeleteTenantSettings(util.GetAuthToken(request), request.PathValue("id"))
+		if err != nil {
+			http.Error(writer, err.Error(), code)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const DEFAULT_LIMIT = 100
@@ -53,6 +54,30 @@ func ParseOffset(str string) (limit int, err error) {
 	return
 }
 
+// ParseTime parses RFC3339 timestamps; an empty string results in the zero time
+func ParseTime(str string, name string) (result time.Time, err error) {
+	if str == "" {
+		return result, nil
+	}
+	result, err = time.Parse(time.RFC3339, str)
+	if err != nil {
+		return result, errors.New("unable to parse " + name + ", expect RFC3339 timestamp")
+	}
+	return result, nil
+}
+
+// ParseBool parses boolean query parameters; an empty string results in false
+func ParseBool(str string, name string) (result bool, err error) {
+	if str == "" {
+		return false, nil
+	}
+	result, err = strconv.ParseBool(str)
+	if err != nil {
+		return result, errors.New("unable to parse " + name + ", expect boolean")
+	}
+	return result, nil
+}
+
 func ParseSort(str string, fields []string) (field string, asc bool, err error) {
 	if len(fields) == 0 {
 		debug.PrintStack()
@@ -27,7 +27,9 @@ import (
 	"net/http"
 	"net/url"
 	"runtime/debug"
+	"strconv"
 	"time"
+	"unicode/utf8"
 
 	"github.com/SENERGY-Platform/process-incident-api/lib/camunda/cache"
 	"github.com/SENERGY-Platform/process-incident-api/lib/camunda/shards"
@@ -109,6 +111,45 @@ func (this *Camunda) GetProcessName(id string, tenantId string) (name string, er
 	return result.Name, err
 }
 
+type ProcessDefinitionTenantWrapper struct {
+	TenantId string `json:"tenantId"`
+}
+
+// CheckProcessDefinitionAccess checks if the process-definition is deployed in the shard of the user and is owned by the user;
+// users without shard have no process-definitions and are denied
+func (this *Camunda) CheckProcessDefinitionAccess(processDefinitionId string, userId string) (allowed bool, err error) {
+	shard, err := this.shards.GetShardForUser(userId)
+	if errors.Is(err, shards.ErrorNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	request, err := http.NewRequest("GET", shard+"/engine-rest/process-definition/"+url.PathEscape(processDefinitionId), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != 200 {
+		temp, _ := io.ReadAll(resp.Body)
+		return false, errors.New("unexpected response: " + resp.Status + " " + string(temp))
+	}
+	result := ProcessDefinitionTenantWrapper{}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return false, err
+	}
+	return result.TenantId == userId, nil
+}
+
 func (this *Camunda) StartProcess(processDefinitionId string, userId string) (err error) {
 	shard, err := this.shards.EnsureShardForUser(userId)
 	if err != nil {
@@ -192,6 +233,259 @@ func (this *Camunda) StartProcessWithBusinessKey(processDefinitionId string, bus
 	return nil
 }
 
+// StartProcessWithVariables starts the process with the given start-parameters; unknown variables are ignored
+func (this *Camunda) StartProcessWithVariables(processDefinitionId string, businessKey string, variables map[string]messages.CamundaVariable, userId string) (err error) {
+	shard, err := this.shards.EnsureShardForUser(userId)
+	if err != nil {
+		return err
+	}
+
+	parameters, err := this.getProcessParameters(shard, processDefinitionId)
+	if err != nil {
+		return err
+	}
+	startVariables := map[string]messages.CamundaVariable{}
+	for key := range parameters {
+		variable, ok := variables[key]
+		if !ok {
+			return errors.New("missing start-parameter " + key + " for restart")
+		}
+		startVariables[key] = variable
+	}
+
+	message := map[string]interface{}{"variables": startVariables}
+	if businessKey != "" {
+		message["businessKey"] = businessKey
+	}
+
+	b := new(bytes.Buffer)
+	err = json.NewEncoder(b).Encode(message)
+	if err != nil {
+		return
+	}
+	if this.config.Debug == true {
+		log.Println("DEBUG: start process definition with variables at camunda:", processDefinitionId)
+	}
+	req, err := http.NewRequest("POST", shard+"/engine-rest/process-definition/"+url.QueryEscape(processDefinitionId)+"/submit-form", b)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		temp, _ := io.ReadAll(resp.Body)
+		err = errors.New(resp.Status + " " + string(temp))
+		return
+	}
+	return nil
+}
+
+type HistoricVariableInstance struct {
+	Name               string      `json:"name"`
+	Type               string      `json:"type"`
+	Value              interface{} `json:"value"`
+	ValueInfo          interface{} `json:"valueInfo"`
+	ActivityInstanceId string      `json:"activityInstanceId"`
+}
+
+// HistoricVariableUpdate is an entry of /history/detail with variableUpdates=true
+type HistoricVariableUpdate struct {
+	VariableName       string      `json:"variableName"`
+	VariableType       string      `json:"variableType"`
+	Value              interface{} `json:"value"`
+	ValueInfo          interface{} `json:"valueInfo"`
+	Revision           int64       `json:"revision"`
+	ActivityInstanceId string      `json:"activityInstanceId"`
+}
+
+// GetStartVariables returns the process variables of the instance matching the start-parameters of the process-definition.
+// only variables of the process-instance scope are used; values are taken from the earliest revision of each variable,
+// so that changes after the start of the instance are ignored
+func (this *Camunda) GetStartVariables(processInstanceId string, processDefinitionId string, userId string) (result map[string]messages.CamundaVariable, err error) {
+	shard, err := this.shards.EnsureShardForUser(userId)
+	if err != nil {
+		return result, err
+	}
+	parameters, err := this.getProcessParameters(shard, processDefinitionId)
+	if err != nil {
+		return result, err
+	}
+	result = map[string]messages.CamundaVariable{}
+	if len(parameters) == 0 {
+		return result, nil
+	}
+
+	query := url.Values{}
+	query.Set("processInstanceId", processInstanceId)
+	query.Set("variableUpdates", "true")
+	query.Set("deserializeValues", "false")
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(shard + "/engine-rest/history/detail?" + query.Encode())
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		pl, _ := io.ReadAll(resp.Body)
+		err = fmt.Errorf("unable to load variables of process-instance: %v", string(pl))
+		return result, err
+	}
+	updates := []HistoricVariableUpdate{}
+	err = json.NewDecoder(resp.Body).Decode(&updates)
+	if err != nil {
+		return result, err
+	}
+	revisions := map[string]int64{}
+	for _, update := range updates {
+		if _, isParameter := parameters[update.VariableName]; !isParameter {
+			continue
+		}
+		if update.ActivityInstanceId != processInstanceId {
+			continue
+		}
+		if revision, ok := revisions[update.VariableName]; ok && revision <= update.Revision {
+			continue
+		}
+		revisions[update.VariableName] = update.Revision
+		result[update.VariableName] = messages.CamundaVariable{
+			Value:     update.Value,
+			Type:      update.VariableType,
+			ValueInfo: update.ValueInfo,
+		}
+	}
+	return result, nil
+}
+
+type ExternalTaskActivityWrapper struct {
+	ActivityId string `json:"activityId"`
+}
+
+// getIncidentTarget resolves the type, configuration (job-id or external-task-id) and activity of an incident.
+// incidents not known to camunda by their id are handled as external-task incidents, reported by a worker
+func (this *Camunda) getIncidentTarget(shard string, incident messages.Incident) (incidentType string, configuration string, activityId string, err error) {
+	if incident.IncidentType != "" && incident.Configuration != "" && incident.ActivityId != "" {
+		return incident.IncidentType, incident.Configuration, incident.ActivityId, nil
+	}
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(shard + "/engine-rest/incident/" + url.PathEscape(incident.Id))
+	if err != nil {
+		return "", "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		result := messages.CamundaIncident{}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		return result.IncidentType, result.Configuration, result.ActivityId, err
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		pl, _ := io.ReadAll(resp.Body)
+		return "", "", "", fmt.Errorf("unable to load incident: %v", string(pl))
+	}
+	if incident.ExternalTaskId == "" {
+		return "", "", "", errors.New("unknown incident " + incident.Id)
+	}
+	taskResp, err := client.Get(shard + "/engine-rest/external-task/" + url.PathEscape(incident.ExternalTaskId))
+	if err != nil {
+		return "", "", "", err
+	}
+	defer taskResp.Body.Close()
+	if taskResp.StatusCode != http.StatusOK {
+		pl, _ := io.ReadAll(taskResp.Body)
+		return "", "", "", fmt.Errorf("unable to load external-task: %v", string(pl))
+	}
+	task := ExternalTaskActivityWrapper{}
+	err = json.NewDecoder(taskResp.Body).Decode(&task)
+	return messages.CamundaIncidentTypeFailedExternalTask, incident.ExternalTaskId, task.ActivityId, err
+}
+
+// RetryIncident resets the retries of the failed job or external-task of the incident; the process-instance keeps its state
+func (this *Camunda) RetryIncident(incident messages.Incident, retries int64, userId string) (err error) {
+	shard, err := this.shards.EnsureShardForUser(userId)
+	if err != nil {
+		return err
+	}
+	incidentType, configuration, _, err := this.getIncidentTarget(shard, incident)
+	if err != nil {
+		return err
+	}
+	var endpoint string
+	switch incidentType {
+	case messages.CamundaIncidentTypeFailedJob:
+		endpoint = shard + "/engine-rest/job/" + url.PathEscape(configuration) + "/retries"
+	case messages.CamundaIncidentTypeFailedExternalTask:
+		endpoint = shard + "/engine-rest/external-task/" + url.PathEscape(configuration) + "/retries"
+	default:
+		return errors.New("retry of incidents with type " + incidentType + " not supported")
+	}
+	b := new(bytes.Buffer)
+	err = json.NewEncoder(b).Encode(map[string]interface{}{"retries": retries})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("PUT", endpoint, b)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		temp, _ := io.ReadAll(resp.Body)
+		return errors.New("unable to set retries: " + resp.Status + " " + string(temp))
+	}
+	return nil
+}
+
+// RestartIncidentActivity cancels the failed activity of the incident and starts the process-instance again before this activity
+func (this *Camunda) RestartIncidentActivity(incident messages.Incident, userId string) (err error) {
+	shard, err := this.shards.EnsureShardForUser(userId)
+	if err != nil {
+		return err
+	}
+	_, _, activityId, err := this.getIncidentTarget(shard, incident)
+	if err != nil {
+		return err
+	}
+	if activityId == "" {
+		return errors.New("unable to find failed activity of incident " + incident.Id)
+	}
+	b := new(bytes.Buffer)
+	err = json.NewEncoder(b).Encode(map[string]interface{}{
+		"instructions": []map[string]interface{}{
+			{"type": "cancel", "activityId": activityId},
+			{"type": "startBeforeActivity", "activityId": activityId},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", shard+"/engine-rest/process-instance/"+url.PathEscape(incident.ProcessInstanceId)+"/modification", b)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		temp, _ := io.ReadAll(resp.Body)
+		return errors.New("unable to modify process-instance: " + resp.Status + " " + string(temp))
+	}
+	return nil
+}
+
 type Variable struct {
 	Value     interface{} `json:"value"`
 	Type      string      `json:"type"`
@@ -230,24 +524,61 @@ func createStartMessage(parameter map[string]interface{}, businessKey string) ma
 	return map[string]interface{}{"variables": variables, "businessKey": businessKey}
 }
 
-func (this *Camunda) GetIncidents() (result []messages.CamundaIncident, err error) {
-	shards, err := this.shards.GetShards()
+// GetJobStacktrace returns the stacktrace of the failed job, truncated to at most maxLength bytes if maxLength > 0
+func (this *Camunda) GetJobStacktrace(jobId string, maxLength int64, userId string) (stacktrace string, err error) {
+	shard, err := this.shards.EnsureShardForUser(userId)
 	if err != nil {
-		return result, err
+		return "", err
 	}
-	for _, shard := range shards {
-		temp, err := this.GetShardIncidents(shard)
-		if err != nil {
-			return result, err
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(shard + "/engine-rest/job/" + url.PathEscape(jobId) + "/stacktrace")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		pl, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("unable to load job stacktrace: %v", string(pl))
+	}
+	var reader io.Reader = resp.Body
+	if maxLength > 0 {
+		reader = io.LimitReader(resp.Body, maxLength)
+	}
+	temp, err := io.ReadAll(reader)
+	return string(trimIncompleteRune(temp)), err
+}
+
+// trimIncompleteRune removes a trailing multi-byte utf-8 character that was cut by a byte limit
+func trimIncompleteRune(b []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(b); i++ {
+		start := len(b) - i
+		if !utf8.RuneStart(b[start]) {
+			continue
+		}
+		if !utf8.FullRune(b[start:]) {
+			return b[:start]
 		}
-		result = append(result, temp...)
+		return b
 	}
-	return result, nil
+	return b
+}
+
+func (this *Camunda) GetShards() (result []string, err error) {
+	return this.shards.GetShards()
 }
 
-func (this *Camunda) GetShardIncidents(shard string) (result []messages.CamundaIncident, err error) {
+// GetShardIncidentsAfter returns a page of the incidents of the shard, sorted by their timestamp. if after is zero, all incidents are returned
+func (this *Camunda) GetShardIncidentsAfter(shard string, after time.Time, firstResult int64, maxResults int64) (result []messages.CamundaIncident, err error) {
+	query := url.Values{}
+	query.Set("sortBy", "incidentTimestamp")
+	query.Set("sortOrder", "asc")
+	query.Set("firstResult", strconv.FormatInt(firstResult, 10))
+	query.Set("maxResults", strconv.FormatInt(maxResults, 10))
+	if !after.IsZero() {
+		query.Set("incidentTimestampAfter", after.Format(messages.CamundaTimeFormat))
+	}
 	client := http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(shard + "/engine-rest/incident")
+	resp, err := client.Get(shard + "/engine-rest/incident?" + query.Encode())
 	if err != nil {
 		return result, err
 	}
@@ -264,6 +595,34 @@ func (this *Camunda) GetShardIncidents(shard string) (result []messages.CamundaI
 	return result, nil
 }
 
+// GetShardHistoricIncidents returns a page of the historic incidents of the shard, sorted by their creation time. zero from and until are ignored
+func (this *Camunda) GetShardHistoricIncidents(shard string, from time.Time, until time.Time, firstResult int64, maxResults int64) (result []messages.CamundaHistoricIncident, err error) {
+	query := url.Values{}
+	query.Set("sortBy", "createTime")
+	query.Set("sortOrder", "asc")
+	query.Set("firstResult", strconv.FormatInt(firstResult, 10))
+	query.Set("maxResults", strconv.FormatInt(maxResults, 10))
+	if !from.IsZero() {
+		query.Set("createTimeAfter", from.Format(messages.CamundaTimeFormat))
+	}
+	if !until.IsZero() {
+		query.Set("createTimeBefore", until.Format(messages.CamundaTimeFormat))
+	}
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(shard + "/engine-rest/history/incident?" + query.Encode())
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		pl, _ := io.ReadAll(resp.Body)
+		err = fmt.Errorf("unable to load historic incidents: %v", string(pl))
+		return result, err
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
+
 func (this *Camunda) GetHistoricProcessInstance(id string, userId string) (result messages.HistoricProcessInstance, err error) {
 	shard, err := this.shards.EnsureShardForUser(userId)
 	if err != nil {
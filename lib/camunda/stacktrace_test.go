@@ -0,0 +1,41 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package camunda
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTrimIncompleteRune(t *testing.T) {
+	text := []byte("error: äöü €")
+	for i := 0; i <= len(text); i++ {
+		result := trimIncompleteRune(text[:i])
+		if !utf8.Valid(result) {
+			t.Error(i, result)
+		}
+		if len(text[:i])-len(result) >= utf8.UTFMax {
+			t.Error("removed too much", i, result)
+		}
+	}
+	if result := trimIncompleteRune(text); string(result) != string(text) {
+		t.Error(string(result))
+	}
+	if result := trimIncompleteRune([]byte("ä")[:1]); len(result) != 0 {
+		t.Error(result)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package camundasource
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
+	"github.com/google/uuid"
+)
+
+const LeaderLeaseName = "camundasource:leader"
+const DefaultLeaderLeaseDuration = 30 * time.Second
+
+var ErrLeadershipLost = errors.New("lost camunda incident polling leadership")
+
+// LeaderElection uses a lease in the database to ensure that only one instance of the service polls camunda.
+// if the leader stops renewing the lease, another instance takes over after the lease expired.
+// the leader checks IsLeader between shards, pages and incidents of a poll, which renews the lease or aborts the poll
+type LeaderElection struct {
+	db        interfaces.Database
+	owner     string
+	duration  time.Duration
+	mux       sync.Mutex
+	isLeader  bool
+	renewedAt time.Time
+}
+
+// NewLeaderElection returns nil if leader election is disabled; a nil *LeaderElection is always leader
+func NewLeaderElection(ctx context.Context, config configuration.Config, db interfaces.Database) (*LeaderElection, error) {
+	if !config.CamundaIncidentLeaderElection {
+		return nil, nil
+	}
+	duration := DefaultLeaderLeaseDuration
+	if config.CamundaIncidentLeaderLeaseDuration != "" {
+		var err error
+		duration, err = time.ParseDuration(config.CamundaIncidentLeaderLeaseDuration)
+		if err != nil {
+			return nil, err
+		}
+	}
+	hostname, _ := os.Hostname()
+	result := &LeaderElection{
+		db:       db,
+		owner:    hostname + "_" + uuid.NewString(),
+		duration: duration,
+	}
+	go func() {
+		<-ctx.Done()
+		//allow fast failover on shutdown
+		err := db.ReleaseLease(LeaderLeaseName, result.owner)
+		if err != nil {
+			log.Println("WARNING: unable to release leader lease", err)
+		}
+	}()
+	return result, nil
+}
+
+// IsLeader acquires or renews the leader lease if needed
+func (this *LeaderElection) IsLeader() bool {
+	if this == nil {
+		return true
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if this.isLeader && time.Since(this.renewedAt) < this.duration/3 {
+		return true
+	}
+	now := time.Now()
+	acquired, current, err := this.db.TryLease(LeaderLeaseName, this.owner, this.owner, this.duration)
+	if err != nil {
+		log.Println("WARNING: unable to acquire leader lease", err)
+		acquired = false
+	}
+	if acquired != this.isLeader {
+		if acquired {
+			log.Println("camunda incident polling: became leader", this.owner)
+		} else {
+			log.Println("camunda incident polling: lost leadership to", current.Owner)
+		}
+	}
+	this.isLeader = acquired
+	if acquired {
+		this.renewedAt = now
+	}
+	return acquired
+}
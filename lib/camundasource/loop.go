@@ -21,14 +21,12 @@ import (
 	"log"
 	"time"
 
-	"github.com/SENERGY-Platform/process-incident-api/lib/client"
 	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
 	"github.com/SENERGY-Platform/process-incident-api/lib/controller"
 	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
-	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
 )
 
-func Start(ctx context.Context, config configuration.Config, camunda interfaces.Camunda, ctrl *controller.Controller) error {
+func Start(ctx context.Context, config configuration.Config, camunda interfaces.Camunda, db interfaces.Database, ctrl *controller.Controller, m Metric) error {
 	interval := time.Second
 	var err error
 	if config.CamundaIncidentRequestInterval != "" && config.CamundaIncidentRequestInterval != "-" {
@@ -39,34 +37,31 @@ func Start(ctx context.Context, config configuration.Config, camunda interfaces.
 	} else {
 		return nil
 	}
+	election, err := NewLeaderElection(ctx, config, db)
+	if err != nil {
+		return err
+	}
+	poller, err := newShardPoller(config, camunda, db, ctrl, m, election)
+	if err != nil {
+		return err
+	}
 	go func() {
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			default:
-				incidents, err := camunda.GetIncidents()
-				if err != nil {
-					log.Println("WARNING: unable to load camunda incidents", err)
+				if !election.IsLeader() {
+					time.Sleep(interval)
 					continue
 				}
-				for _, incident := range incidents {
-					err, _ = ctrl.CreateIncident(client.InternalAdminToken, messages.Incident{
-						Id:                  incident.Id,
-						MsgVersion:          3,
-						ExternalTaskId:      incident.ActivityId,
-						ProcessInstanceId:   incident.ProcessInstanceId,
-						ProcessDefinitionId: incident.ProcessDefinitionId,
-						WorkerId:            "process-incident-worker",
-						ErrorMessage:        incident.IncidentMessage,
-						Time:                time.Now(),
-						TenantId:            incident.TenantId,
-					})
-					if err != nil {
-						log.Println("WARNING: unable to handle camunda incidents", err)
-						continue
-					}
+				shards, err := camunda.GetShards()
+				if err != nil {
+					log.Println("WARNING: unable to load camunda shards", err)
+					time.Sleep(interval)
+					continue
 				}
+				poller.pollShards(shards)
 				time.Sleep(interval)
 			}
 		}
@@ -0,0 +1,241 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package camundasource
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/client"
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/controller"
+	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+)
+
+type Metric interface {
+	NotifyCamundaShardPoll(shard string, duration time.Duration, incidents int, err error)
+}
+
+const DefaultPageSize = 100
+
+// DefaultWatermarkOverlap is subtracted from the watermark, to find incidents with the same timestamp as the watermark
+// and incidents that camunda committed after incidents with newer timestamps; repeated incidents are deduplicated by the controller
+const DefaultWatermarkOverlap = 30 * time.Second
+
+// DefaultMaxAttempts is the count of polls that try to handle an incident, before it is skipped
+const DefaultMaxAttempts = 5
+
+// failureRetention is the time failures of incidents are kept in the database for inspection
+const failureRetention = 30 * 24 * time.Hour
+
+type shardPoller struct {
+	camunda     interfaces.Camunda
+	db          interfaces.Database
+	ctrl        *controller.Controller
+	metric      Metric
+	election    *LeaderElection
+	concurrency int64
+	pageSize    int64
+	overlap     time.Duration
+	maxAttempts int64
+}
+
+func newShardPoller(config configuration.Config, camunda interfaces.Camunda, db interfaces.Database, ctrl *controller.Controller, m Metric, election *LeaderElection) (poller *shardPoller, err error) {
+	poller = &shardPoller{
+		camunda:     camunda,
+		db:          db,
+		ctrl:        ctrl,
+		metric:      m,
+		election:    election,
+		concurrency: config.CamundaIncidentShardConcurrency,
+		pageSize:    config.CamundaIncidentPageSize,
+		overlap:     DefaultWatermarkOverlap,
+		maxAttempts: config.CamundaIncidentMaxAttempts,
+	}
+	if poller.concurrency < 1 {
+		poller.concurrency = 1
+	}
+	if poller.pageSize < 1 {
+		poller.pageSize = DefaultPageSize
+	}
+	if poller.maxAttempts < 1 {
+		poller.maxAttempts = DefaultMaxAttempts
+	}
+	if config.CamundaIncidentWatermarkOverlap != "" {
+		poller.overlap, err = time.ParseDuration(config.CamundaIncidentWatermarkOverlap)
+		if err != nil {
+			return poller, err
+		}
+	}
+	return poller, nil
+}
+
+// pollShards handles the incidents of every shard independently, so that a failing or slow shard does not block the others.
+// the poll is aborted if the leadership is lost while it is running
+func (this *shardPoller) pollShards(shards []string) {
+	wg := sync.WaitGroup{}
+	limit := make(chan struct{}, this.concurrency)
+	for _, shard := range shards {
+		limit <- struct{}{}
+		if !this.election.IsLeader() {
+			<-limit
+			break
+		}
+		wg.Add(1)
+		go func(shard string) {
+			defer wg.Done()
+			defer func() { <-limit }()
+			err := this.pollShard(shard)
+			if errors.Is(err, ErrLeadershipLost) {
+				log.Println("WARNING: abort poll of camunda incidents of shard", shard, err)
+				return
+			}
+			if err != nil {
+				log.Println("WARNING: unable to load camunda incidents of shard", shard, err)
+			}
+		}(shard)
+	}
+	wg.Wait()
+}
+
+func (this *shardPoller) pollShard(shard string) error {
+	start := time.Now()
+	watermark, err := this.db.GetShardWatermark(shard)
+	if err != nil {
+		this.metric.NotifyCamundaShardPoll(shard, time.Since(start), 0, err)
+		return err
+	}
+	incidents, err := this.getIncidentsAfterWatermark(shard, watermark)
+	this.metric.NotifyCamundaShardPoll(shard, time.Since(start), len(incidents), err)
+	if err != nil {
+		return err
+	}
+	//the watermark of incidents handled before a lost leadership may be stored, because the database keeps the newest watermark
+	newWatermark, handleErr := this.handleIncidents(shard, incidents, watermark)
+	if newWatermark.After(watermark) {
+		err = this.db.SetShardWatermark(shard, newWatermark)
+		if err != nil {
+			return err
+		}
+	}
+	return handleErr
+}
+
+// getIncidentsAfterWatermark loads all pages before the incidents are handled, because handled incidents are removed from camunda and would shift the pages
+func (this *shardPoller) getIncidentsAfterWatermark(shard string, watermark time.Time) (result []messages.CamundaIncident, err error) {
+	after := time.Time{}
+	if !watermark.IsZero() {
+		after = watermark.Add(-this.overlap)
+	}
+	for firstResult := int64(0); ; firstResult = firstResult + this.pageSize {
+		if !this.election.IsLeader() {
+			return result, ErrLeadershipLost
+		}
+		page, err := this.camunda.GetShardIncidentsAfter(shard, after, firstResult, this.pageSize)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, page...)
+		if int64(len(page)) < this.pageSize {
+			return result, nil
+		}
+	}
+}
+
+// handleIncidents returns the new watermark; it only advances until the first incident that could not be handled, so that this incident is retried in the next poll.
+// incidents that failed maxAttempts times are skipped, so that they do not stop the watermark permanently.
+// if the leadership is lost, the remaining incidents are left to the new leader and ErrLeadershipLost is returned
+func (this *shardPoller) handleIncidents(shard string, incidents []messages.CamundaIncident, watermark time.Time) (newWatermark time.Time, err error) {
+	newWatermark = watermark
+	advance := true
+	for _, incident := range incidents {
+		if !this.election.IsLeader() {
+			return newWatermark, ErrLeadershipLost
+		}
+		err := handleIncident(this.ctrl, incident)
+		if err != nil && !this.skipFailedIncident(shard, incident, err) {
+			advance = false
+			continue
+		}
+		if !advance {
+			continue
+		}
+		timestamp, err := incident.Timestamp()
+		if err != nil {
+			//the incident is polled again while the watermark is before its timestamp and deduplicated by the controller
+			log.Println("WARNING: unable to parse camunda incident timestamp", incident.IncidentTimestamp, err)
+			continue
+		}
+		if timestamp.After(newWatermark) {
+			newWatermark = timestamp
+		}
+	}
+	return newWatermark, nil
+}
+
+// skipFailedIncident stores the failed attempt and returns true, if the incident failed maxAttempts times
+func (this *shardPoller) skipFailedIncident(shard string, incident messages.CamundaIncident, handleErr error) bool {
+	log.Println("WARNING: unable to handle camunda incident", incident.Id, handleErr)
+	now := time.Now()
+	timestamp, _ := incident.Timestamp()
+	attempts, err := this.db.AddCamundaIncidentFailure(messages.CamundaIncidentFailure{
+		IncidentId:  incident.Id,
+		Shard:       shard,
+		Timestamp:   timestamp,
+		LastError:   handleErr.Error(),
+		LastAttempt: now,
+		ExpiresAt:   now.Add(failureRetention),
+	})
+	if err != nil {
+		log.Println("ERROR: unable to store failed attempt of camunda incident", incident.Id, err)
+		return false
+	}
+	if attempts < this.maxAttempts {
+		return false
+	}
+	log.Println("ERROR: skip camunda incident after", attempts, "failed attempts", incident.Id, incident.ProcessDefinitionId, incident.ProcessInstanceId, handleErr)
+	return true
+}
+
+func handleIncident(ctrl *controller.Controller, incident messages.CamundaIncident) error {
+	timestamp, err := incident.Timestamp()
+	if err != nil {
+		log.Println("WARNING: unable to parse camunda incident timestamp, use current time", incident.IncidentTimestamp, err)
+		timestamp = time.Now()
+	}
+	err, _ = ctrl.CreateIncident(client.InternalAdminToken, messages.Incident{
+		Id:                  incident.Id,
+		MsgVersion:          3,
+		ExternalTaskId:      incident.ActivityId,
+		ProcessInstanceId:   incident.ProcessInstanceId,
+		ProcessDefinitionId: incident.ProcessDefinitionId,
+		WorkerId:            "process-incident-worker",
+		ErrorMessage:        incident.IncidentMessage,
+		Time:                timestamp,
+		TenantId:            incident.TenantId,
+		IncidentType:        incident.IncidentType,
+		ActivityId:          incident.ActivityId,
+		CauseIncidentId:     incident.CauseIncidentId,
+		RootCauseIncidentId: incident.RootCauseIncidentId,
+		JobDefinitionId:     incident.JobDefinitionId,
+		Configuration:       incident.Configuration,
+	})
+	return err
+}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
 	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
@@ -52,31 +53,69 @@ func (this *ClientImpl) GetIncident(token string, id string) (incident messages.
 	return do[messages.IncidentMessage](token, req)
 }
 
-func (this *ClientImpl) FindIncidents(token string, externalTaskId string, processDefinitionId string, processInstanceId string, limit int, offset int, sortBy string, asc bool) (incidents []messages.IncidentMessage, err error, code int) {
-	query := url.Values{}
-	query.Add("limit", strconv.Itoa(limit))
-	query.Add("offset", strconv.Itoa(offset))
-	sort := sortBy
-	if asc {
+func (this *ClientImpl) GetIncidentDetails(token string, id string) (incident messages.IncidentMessage, err error, errCode int) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/incidents/%v?details=true", this.serverUrl, url.PathEscape(id)), nil)
+	if err != nil {
+		return incident, err, 0
+	}
+	return do[messages.IncidentMessage](token, req)
+}
+
+func (this *ClientImpl) FindIncidents(token string, options messages.FindIncidentsOptions) (incidents []messages.IncidentMessage, nextCursor string, err error, code int) {
+	query := getIncidentFilterQuery(options.IncidentFilter)
+	query.Add("limit", strconv.Itoa(options.Limit))
+	query.Add("offset", strconv.Itoa(options.Offset))
+	if options.Cursor != "" {
+		query.Add("cursor", options.Cursor)
+	}
+	sort := options.SortBy
+	if options.Asc {
 		sort = sort + ".asc"
 	} else {
 		sort = sort + ".desc"
 	}
 	query.Add("sort", sort)
-	if externalTaskId != "" {
-		query.Add("external_task_id", externalTaskId)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/incidents?"+query.Encode(), this.serverUrl), nil)
+	if err != nil {
+		return incidents, nextCursor, err, 0
+	}
+	incidents, header, err, code := doWithHeader[[]messages.IncidentMessage](token, req)
+	return incidents, header.Get("X-Next-Cursor"), err, code
+}
+
+func (this *ClientImpl) CountIncidents(token string, filter messages.IncidentFilter) (count int64, err error, code int) {
+	query := getIncidentFilterQuery(filter)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/incidents/count?"+query.Encode(), this.serverUrl), nil)
+	if err != nil {
+		return count, err, 0
+	}
+	return do[int64](token, req)
+}
+
+func getIncidentFilterQuery(filter messages.IncidentFilter) url.Values {
+	query := url.Values{}
+	if filter.ExternalTaskId != "" {
+		query.Add("external_task_id", filter.ExternalTaskId)
 	}
-	if processDefinitionId != "" {
-		query.Add("process_definition_id", processDefinitionId)
+	if filter.ProcessDefinitionId != "" {
+		query.Add("process_definition_id", filter.ProcessDefinitionId)
 	}
-	if processInstanceId != "" {
-		query.Add("process_instance_id", processInstanceId)
+	if filter.ProcessInstanceId != "" {
+		query.Add("process_instance_id", filter.ProcessInstanceId)
 	}
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/incidents?"+query.Encode(), this.serverUrl), nil)
-	if err != nil {
-		return incidents, err, 0
+	if !filter.From.IsZero() {
+		query.Add("from", filter.From.Format(time.RFC3339Nano))
+	}
+	if !filter.Until.IsZero() {
+		query.Add("until", filter.Until.Format(time.RFC3339Nano))
 	}
-	return do[[]messages.IncidentMessage](token, req)
+	if filter.Search != "" {
+		query.Add("search", filter.Search)
+	}
+	if filter.Status != "" {
+		query.Add("status", filter.Status)
+	}
+	return query
 }
 
 func (this *ClientImpl) CreateIncident(token string, incident messages.Incident) (err error, code int) {
@@ -91,6 +130,38 @@ func (this *ClientImpl) CreateIncident(token string, incident messages.Incident)
 	return doVoid(token, req)
 }
 
+func (this *ClientImpl) SetIncidentStatus(token string, id string, status string) (err error, code int) {
+	body, err := json.Marshal(messages.IncidentStatusUpdate{Status: status})
+	if err != nil {
+		return err, 0
+	}
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/incidents/%v/status", this.serverUrl, url.PathEscape(id)), bytes.NewBuffer(body))
+	if err != nil {
+		return err, 0
+	}
+	return doVoid(token, req)
+}
+
+func (this *ClientImpl) AddIncidentComment(token string, incidentId string, comment messages.IncidentComment) (result messages.IncidentComment, err error, code int) {
+	body, err := json.Marshal(comment)
+	if err != nil {
+		return result, err, 0
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%v/incidents/%v/comments", this.serverUrl, url.PathEscape(incidentId)), bytes.NewBuffer(body))
+	if err != nil {
+		return result, err, 0
+	}
+	return do[messages.IncidentComment](token, req)
+}
+
+func (this *ClientImpl) ListIncidentComments(token string, incidentId string) (comments []messages.IncidentComment, err error, code int) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/incidents/%v/comments", this.serverUrl, url.PathEscape(incidentId)), nil)
+	if err != nil {
+		return comments, err, 0
+	}
+	return do[[]messages.IncidentComment](token, req)
+}
+
 func (this *ClientImpl) SetOnIncidentHandler(token string, incident messages.OnIncident) (err error, code int) {
 	body, err := json.Marshal(incident)
 	if err != nil {
@@ -103,6 +174,33 @@ func (this *ClientImpl) SetOnIncidentHandler(token string, incident messages.OnI
 	return doVoid(token, req)
 }
 
+func (this *ClientImpl) GetOnIncidentHandler(token string, processDefinitionId string) (handler messages.OnIncident, err error, code int) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/on-incident-handler/%v", this.serverUrl, url.PathEscape(processDefinitionId)), nil)
+	if err != nil {
+		return handler, err, 0
+	}
+	return do[messages.OnIncident](token, req)
+}
+
+func (this *ClientImpl) ListOnIncidentHandlers(token string, limit int, offset int) (handlers []messages.OnIncident, err error, code int) {
+	query := url.Values{}
+	query.Add("limit", strconv.Itoa(limit))
+	query.Add("offset", strconv.Itoa(offset))
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/on-incident-handler?"+query.Encode(), this.serverUrl), nil)
+	if err != nil {
+		return handlers, err, 0
+	}
+	return do[[]messages.OnIncident](token, req)
+}
+
+func (this *ClientImpl) DeleteOnIncidentHandler(token string, processDefinitionId string) (err error, code int) {
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%v/on-incident-handler/%v", this.serverUrl, url.PathEscape(processDefinitionId)), nil)
+	if err != nil {
+		return err, 0
+	}
+	return doVoid(token, req)
+}
+
 func (this *ClientImpl) DeleteIncidentByProcessInstanceId(token string, id string) (err error, code int) {
 	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%v/process-instances/%v", this.serverUrl, url.PathEscape(id)), nil)
 	if err != nil {
@@ -119,23 +217,125 @@ func (this *ClientImpl) DeleteIncidentByProcessDefinitionId(token string, id str
 	return doVoid(token, req)
 }
 
+func (this *ClientImpl) StartBackfill(token string, request messages.BackfillRequest) (job messages.BackfillJob, err error, code int) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return job, err, 0
+	}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%v/backfills", this.serverUrl), bytes.NewBuffer(body))
+	if err != nil {
+		return job, err, 0
+	}
+	return do[messages.BackfillJob](token, req)
+}
+
+func (this *ClientImpl) GetBackfill(token string, id string) (job messages.BackfillJob, err error, code int) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/backfills/%v", this.serverUrl, url.PathEscape(id)), nil)
+	if err != nil {
+		return job, err, 0
+	}
+	return do[messages.BackfillJob](token, req)
+}
+
+func (this *ClientImpl) ListBackfills(token string) (jobs []messages.BackfillJob, err error, code int) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/backfills", this.serverUrl), nil)
+	if err != nil {
+		return jobs, err, 0
+	}
+	return do[[]messages.BackfillJob](token, req)
+}
+
+func (this *ClientImpl) SetNotificationChannel(token string, channel messages.NotificationChannel) (result messages.NotificationChannel, err error, code int) {
+	body, err := json.Marshal(channel)
+	if err != nil {
+		return result, err, 0
+	}
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/notification-channels", this.serverUrl), bytes.NewBuffer(body))
+	if err != nil {
+		return result, err, 0
+	}
+	return do[messages.NotificationChannel](token, req)
+}
+
+func (this *ClientImpl) GetNotificationChannel(token string, id string) (channel messages.NotificationChannel, err error, code int) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/notification-channels/%v", this.serverUrl, url.PathEscape(id)), nil)
+	if err != nil {
+		return channel, err, 0
+	}
+	return do[messages.NotificationChannel](token, req)
+}
+
+func (this *ClientImpl) ListNotificationChannels(token string, limit int, offset int) (channels []messages.NotificationChannel, err error, code int) {
+	query := url.Values{}
+	query.Add("limit", strconv.Itoa(limit))
+	query.Add("offset", strconv.Itoa(offset))
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/notification-channels?"+query.Encode(), this.serverUrl), nil)
+	if err != nil {
+		return channels, err, 0
+	}
+	return do[[]messages.NotificationChannel](token, req)
+}
+
+func (this *ClientImpl) DeleteNotificationChannel(token string, id string) (err error, code int) {
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%v/notification-channels/%v", this.serverUrl, url.PathEscape(id)), nil)
+	if err != nil {
+		return err, 0
+	}
+	return doVoid(token, req)
+}
+
+func (this *ClientImpl) SetTenantSettings(token string, settings messages.TenantSettings) (err error, code int) {
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return err, 0
+	}
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%v/tenant-settings/%v", this.serverUrl, url.PathEscape(settings.TenantId)), bytes.NewBuffer(body))
+	if err != nil {
+		return err, 0
+	}
+	return doVoid(token, req)
+}
+
+func (this *ClientImpl) GetTenantSettings(token string, tenantId string) (settings messages.TenantSettings, err error, code int) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%v/tenant-settings/%v", this.serverUrl, url.PathEscape(tenantId)), nil)
+	if err != nil {
+		return settings, err, 0
+	}
+	return do[messages.TenantSettings](token, req)
+}
+
+func (this *ClientImpl) DeleteTenantSettings(token string, tenantId string) (err error, code int) {
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%v/tenant-settings/%v", this.serverUrl, url.PathEscape(tenantId)), nil)
+	if err != nil {
+		return err, 0
+	}
+	return doVoid(token, req)
+}
+
 func do[T any](token string, req *http.Request) (result T, err error, code int) {
+	result, _, err, code = doWithHeader[T](token, req)
+	return result, err, code
+}
+
+// doWithHeader is do, additionally returning the response header (e.g. for X-Next-Cursor)
+func doWithHeader[T any](token string, req *http.Request) (result T, header http.Header, err error, code int) {
+	header = http.Header{}
 	req.Header.Set("Authorization", token)
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return result, err, http.StatusInternalServerError
+		return result, header, err, http.StatusInternalServerError
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode > 299 {
 		temp, _ := io.ReadAll(resp.Body) //read error response end ensure that resp.Body is read to EOF
-		return result, fmt.Errorf("unexpected statuscode %v: %v", resp.StatusCode, string(temp)), resp.StatusCode
+		return result, resp.Header, fmt.Errorf("unexpected statuscode %v: %v", resp.StatusCode, string(temp)), resp.StatusCode
 	}
 	err = json.NewDecoder(resp.Body).Decode(&result)
 	if err != nil {
 		_, _ = io.ReadAll(resp.Body) //ensure resp.Body is read to EOF
-		return result, err, http.StatusInternalServerError
+		return result, resp.Header, err, http.StatusInternalServerError
 	}
-	return result, nil, resp.StatusCode
+	return result, resp.Header, nil, resp.StatusCode
 }
 
 func doVoid(token string, req *http.Request) (err error, code int) {
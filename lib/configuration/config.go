@@ -27,18 +27,59 @@ import (
 )
 
 type Config struct {
-	MetricsPort                    string `json:"metrics_port"`
-	ShardsDb                       string `json:"shards_db"`
-	MongoUrl                       string `json:"mongo_url"`
-	MongoDatabaseName              string `json:"mongo_database_name"`
-	MongoIncidentCollectionName    string `json:"mongo_incident_collection_name"`
-	MongoOnIncidentCollectionName  string `json:"mongo_on_incident_collection_name"`
-	ApiPort                        string `json:"api_port"`
-	ApiLog                         bool   `json:"api_log"`
-	Debug                          bool   `json:"debug"`
-	NotificationUrl                string `json:"notification_url"`
-	DeveloperNotificationUrl       string `json:"developer_notification_url"`
-	CamundaIncidentRequestInterval string `json:"camunda_incident_request_interval"`
+	MetricsPort                        string   `json:"metrics_port"`
+	ShardsDb                           string   `json:"shards_db"`
+	MongoUrl                           string   `json:"mongo_url"`
+	MongoDatabaseName                  string   `json:"mongo_database_name"`
+	MongoIncidentCollectionName        string   `json:"mongo_incident_collection_name"`
+	MongoOnIncidentCollectionName      string   `json:"mongo_on_incident_collection_name"`
+	MongoCommentCollectionName         string   `json:"mongo_comment_collection_name"`
+	MongoWatermarkCollectionName       string   `json:"mongo_watermark_collection_name"`
+	MongoLeaseCollectionName           string   `json:"mongo_lease_collection_name"`
+	MongoOutboxCollectionName          string   `json:"mongo_outbox_collection_name"`
+	MongoChannelCollectionName         string   `json:"mongo_channel_collection_name"`
+	MongoTenantSettingsCollectionName  string   `json:"mongo_tenant_settings_collection_name"`
+	MongoDigestCollectionName          string   `json:"mongo_digest_collection_name"`
+	MongoRateLimitCollectionName       string   `json:"mongo_rate_limit_collection_name"`
+	MongoPendingRestartCollectionName  string   `json:"mongo_pending_restart_collection_name"`
+	MongoBackfillCollectionName        string   `json:"mongo_backfill_collection_name"`
+	MongoPollFailureCollectionName     string   `json:"mongo_poll_failure_collection_name"`
+	ApiPort                            string   `json:"api_port"`
+	ApiLog                             bool     `json:"api_log"`
+	Debug                              bool     `json:"debug"`
+	NotificationUrl                    string   `json:"notification_url"`
+	DeveloperNotificationUrl           string   `json:"developer_notification_url"`
+	NotificationDispatchInterval       string   `json:"notification_dispatch_interval"` //"-" disables the dispatcher of this instance
+	NotificationMaxAttempts            int64    `json:"notification_max_attempts"`      //failed notifications are dead-lettered after this count of attempts
+	NotificationRetryBaseDelay         string   `json:"notification_retry_base_delay"`  //delay after the first failed attempt; doubled for every further attempt
+	NotificationRetryMaxDelay          string   `json:"notification_retry_max_delay"`
+	NotificationDeadLetterRetention    string   `json:"notification_dead_letter_retention"` //time dead-lettered notifications are kept in the outbox; defaults to 720h
+	NotificationDigestInterval         string   `json:"notification_digest_interval"`       //interval of digests, if the tenant has no digest_interval; defaults to 15m
+	NotificationRateLimit              int64    `json:"notification_rate_limit"`            //max immediate incident notifications per tenant within notification_rate_limit_window; 0 = unlimited
+	NotificationRateLimitWindow        string   `json:"notification_rate_limit_window"`     //defaults to 1h
+	NotificationDefaultLocale          string   `json:"notification_default_locale"`        //used for tenants without locale setting; defaults to en
+	NotificationTemplatesFile          string   `json:"notification_templates_file"`        //optional json file to override templates by name by locale
+	NotificationChannelAllowedHosts    []string `json:"notification_channel_allowed_hosts"` //webhook and chat hosts, that may resolve to internal addresses; "*.example.com" matches subdomains
+	NotificationChannelDeniedHosts     []string `json:"notification_channel_denied_hosts"`  //webhook and chat hosts, that may never be used
+	SmtpHost                           string   `json:"smtp_host"`                          //email notification channels are only available if set
+	SmtpPort                           string   `json:"smtp_port"`
+	SmtpUser                           string   `json:"smtp_user"`
+	SmtpPassword                       string   `json:"smtp_password"`
+	SmtpFrom                           string   `json:"smtp_from"`
+	CamundaIncidentRequestInterval     string   `json:"camunda_incident_request_interval"`
+	CamundaIncidentLeaderElection      bool     `json:"camunda_incident_leader_election"`       //only one instance polls camunda for incidents
+	CamundaIncidentLeaderLeaseDuration string   `json:"camunda_incident_leader_lease_duration"` //time until another instance takes over if the leader stops; defaults to 30s
+	CamundaIncidentShardConcurrency    int64    `json:"camunda_incident_shard_concurrency"`     //count of shards polled in parallel
+	CamundaIncidentPageSize            int64    `json:"camunda_incident_page_size"`
+	CamundaIncidentWatermarkOverlap    string   `json:"camunda_incident_watermark_overlap"` //incidents are polled from the watermark minus this overlap, to find incidents committed after newer ones; defaults to 30s
+	CamundaIncidentMaxAttempts         int64    `json:"camunda_incident_max_attempts"`      //failed incidents are skipped and the watermark advances past them after this count of attempts; defaults to 5
+	IncidentStackTraceMaxLength        int64    `json:"incident_stack_trace_max_length"`    //max bytes of stored stack traces; 0 = unlimited; -1 = disabled
+	IncidentDeduplicationWindow        string   `json:"incident_deduplication_window"`
+	IncidentRestartCheckInterval       string   `json:"incident_restart_check_interval"`  //interval to run due delayed restarts; "-" disables the check of this instance
+	IncidentBackfillJobRetention       string   `json:"incident_backfill_job_retention"`  //time backfill jobs are stored after their last progress; defaults to 168h
+	IncidentDefaultSeverity            string   `json:"incident_default_severity"`        //severity of incidents without matching severity rule; defaults to medium
+	SharedIncidentDeduplication        bool     `json:"shared_incident_deduplication"`    //store deduplication state as leases in mongodb, shared by all instances
+	HandledIncidentsMemcachedUrls      []string `json:"handled_incidents_memcached_urls"` //optional l2 of the local deduplication cache, if shared_incident_deduplication is false
 }
 
 // loads config from json in location and used environment variables (e.g ZookeeperUrl --> ZOOKEEPER_URL)
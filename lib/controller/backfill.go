@@ -0,0 +1,288 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"github.com/google/uuid"
+)
+
+const defaultBackfillPageSize = 100
+
+const DefaultBackfillJobRetention = 7 * 24 * time.Hour
+
+// backfillStaleAfter is the time after which running jobs without progress are marked as failed, because their instance stopped
+const backfillStaleAfter = 15 * time.Minute
+
+// backfillJobErrorKey is used in BackfillJob.Errors for errors that do not belong to a shard
+const backfillJobErrorKey = "job"
+
+var ErrBackfillInterrupted = errors.New("backfill interrupted by shutdown of the instance")
+var ErrBackfillStale = errors.New("backfill without progress; the instance running the job may have been stopped")
+
+// parseBackfillJobRetention returns DefaultBackfillJobRetention for empty strings
+func parseBackfillJobRetention(value string) (time.Duration, error) {
+	if value == "" {
+		return DefaultBackfillJobRetention, nil
+	}
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid backfill job retention: %w", err)
+	}
+	if result <= 0 {
+		return 0, fmt.Errorf("invalid backfill job retention: must be positive")
+	}
+	return result, nil
+}
+
+// StartBackfill starts an asynchronous job, which stores historic incidents of camunda.
+// resolved incidents are stored without handling; missing open incidents are handled like incidents reported by camunda.
+// the job state is stored in the database and removed after the configured retention since its last progress;
+// jobs of stopped instances are marked as failed when they are read after backfillStaleAfter without progress
+func (this *Controller) StartBackfill(token string, request messages.BackfillRequest) (job messages.BackfillJob, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return job, err, http.StatusUnauthorized
+	}
+	if !jwtToken.IsAdmin() {
+		return job, errors.New("only admins may start backfills"), http.StatusForbidden
+	}
+	if !request.From.IsZero() && !request.Until.IsZero() && request.Until.Before(request.From) {
+		return job, errors.New("until may not be before from"), http.StatusBadRequest
+	}
+	shards, err := this.camunda.GetShards()
+	if err != nil {
+		log.Printf("ERROR: %+v \n", err)
+		return job, errors.New("unable to load shards"), http.StatusInternalServerError
+	}
+	for _, shard := range request.Shards {
+		if !slices.Contains(shards, shard) {
+			return job, errors.New("unknown shard " + shard), http.StatusBadRequest
+		}
+	}
+	if len(request.Shards) > 0 {
+		shards = request.Shards
+	}
+	job = messages.BackfillJob{
+		Id:      uuid.NewString(),
+		Request: request,
+		Status:  messages.BackfillStatusRunning,
+		Started: time.Now(),
+		Updated: time.Now(),
+		Errors:  map[string]string{},
+	}
+	err = this.saveBackfill(job)
+	if err != nil {
+		log.Printf("ERROR: %+v \n", err)
+		return job, errors.New("unable to store backfill"), http.StatusInternalServerError
+	}
+	go this.runBackfill(job, shards)
+	return job, nil, http.StatusOK
+}
+
+func (this *Controller) GetBackfill(token string, id string) (job messages.BackfillJob, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return job, err, http.StatusUnauthorized
+	}
+	if !jwtToken.IsAdmin() {
+		return job, errors.New("only admins may read backfills"), http.StatusForbidden
+	}
+	job, exists, err := this.db.GetBackfillJob(id)
+	if err != nil {
+		log.Printf("ERROR: %+v \n", err)
+		return job, errors.New("unable to load backfill"), http.StatusInternalServerError
+	}
+	if !exists {
+		return job, errors.New("not found"), http.StatusNotFound
+	}
+	return this.failStaleBackfill(job), nil, http.StatusOK
+}
+
+func (this *Controller) ListBackfills(token string) (jobs []messages.BackfillJob, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return jobs, err, http.StatusUnauthorized
+	}
+	if !jwtToken.IsAdmin() {
+		return jobs, errors.New("only admins may read backfills"), http.StatusForbidden
+	}
+	jobs, err = this.db.ListBackfillJobs()
+	if err != nil {
+		log.Printf("ERROR: %+v \n", err)
+		return jobs, errors.New("unable to load backfills"), http.StatusInternalServerError
+	}
+	for i, job := range jobs {
+		jobs[i] = this.failStaleBackfill(job)
+	}
+	return jobs, nil, http.StatusOK
+}
+
+// failStaleBackfill marks running jobs without progress since backfillStaleAfter as failed
+func (this *Controller) failStaleBackfill(job messages.BackfillJob) messages.BackfillJob {
+	if job.Status != messages.BackfillStatusRunning || time.Since(job.Updated) < backfillStaleAfter {
+		return job
+	}
+	job.Status = messages.BackfillStatusFailed
+	job.Finished = time.Now()
+	job.Errors[backfillJobErrorKey] = ErrBackfillStale.Error()
+	this.storeBackfillProgress(job)
+	return job
+}
+
+func (this *Controller) saveBackfill(job messages.BackfillJob) error {
+	return this.db.SaveBackfillJob(job, time.Now().Add(this.backfillJobRetention))
+}
+
+// runBackfill is the only writer of the job; the progress is stored after every page.
+// the job stops with ErrBackfillInterrupted, if the service context is canceled
+func (this *Controller) runBackfill(job messages.BackfillJob, shards []string) {
+	deploymentNames := map[string]string{}
+	for _, shard := range shards {
+		if this.ctx.Err() != nil {
+			job.Errors[shard] = ErrBackfillInterrupted.Error()
+			continue
+		}
+		err := this.backfillShard(&job, shard, deploymentNames)
+		if err != nil {
+			this.logger.Error("unable to backfill incidents of shard", "snrgy-log-type", "warning", "error", err.Error(), "shard", shard, "backfill", job.Id)
+			job.Errors[shard] = err.Error()
+			this.storeBackfillProgress(job)
+		}
+	}
+	job.Finished = time.Now()
+	if len(job.Errors) > 0 {
+		job.Status = messages.BackfillStatusFailed
+	} else {
+		job.Status = messages.BackfillStatusFinished
+	}
+	this.storeBackfillProgress(job)
+}
+
+func (this *Controller) storeBackfillProgress(job messages.BackfillJob) {
+	job.Updated = time.Now()
+	err := this.saveBackfill(job)
+	if err != nil {
+		this.logger.Error("unable to store backfill progress", "snrgy-log-type", "warning", "error", err.Error(), "backfill", job.Id)
+	}
+}
+
+func (this *Controller) backfillShard(job *messages.BackfillJob, shard string, deploymentNames map[string]string) error {
+	pageSize := this.config.CamundaIncidentPageSize
+	if pageSize < 1 {
+		pageSize = defaultBackfillPageSize
+	}
+	for firstResult := int64(0); ; firstResult = firstResult + pageSize {
+		page, err := this.camunda.GetShardHistoricIncidents(shard, job.Request.From, job.Request.Until, firstResult, pageSize)
+		if err != nil {
+			return err
+		}
+		for _, historic := range page {
+			if this.ctx.Err() != nil {
+				return ErrBackfillInterrupted
+			}
+			job.Read = job.Read + 1
+			incident := this.getBackfillIncident(historic, deploymentNames)
+			err = this.ValidateIncident(incident)
+			if err != nil {
+				this.logger.Warn("skip invalid historic incident", "snrgy-log-type", "warning", "error", err.Error(), "incident-id", historic.Id, "shard", shard, "backfill", job.Id)
+				job.Invalid = job.Invalid + 1
+				continue
+			}
+			inserted := false
+			if historic.Open {
+				inserted, err = this.handleOpenBackfillIncident(incident)
+			} else {
+				inserted, err = this.db.InsertIncidentIfMissing(incident)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to store incident %v: %w", incident.Id, err)
+			}
+			if inserted {
+				job.Inserted = job.Inserted + 1
+			} else {
+				job.Skipped = job.Skipped + 1
+			}
+		}
+		this.storeBackfillProgress(*job)
+		if int64(len(page)) < pageSize {
+			return nil
+		}
+	}
+}
+
+// handleOpenBackfillIncident handles missing open incidents like incidents reported by camunda (stop, restart, notify),
+// because the camunda poller does not report incidents older than its watermark again
+func (this *Controller) handleOpenBackfillIncident(incident messages.Incident) (handled bool, err error) {
+	_, exists, err := this.db.GetIncidents(incident.Id, incident.TenantId)
+	if err != nil || exists {
+		return false, err
+	}
+	err, _ = this.handleIncident(incident)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (this *Controller) getBackfillIncident(historic messages.CamundaHistoricIncident, deploymentNames map[string]string) (incident messages.Incident) {
+	created, err := time.Parse(messages.CamundaTimeFormat, historic.CreateTime)
+	if err != nil {
+		log.Println("WARNING: unable to parse historic incident create time", historic.CreateTime, err)
+	}
+	name, ok := deploymentNames[historic.ProcessDefinitionId]
+	if !ok {
+		name, err = this.camunda.GetProcessName(historic.ProcessDefinitionId, historic.TenantId)
+		if err != nil {
+			//the process-definition may have been deleted since the incident occurred
+			name = historic.ProcessDefinitionId
+		}
+		deploymentNames[historic.ProcessDefinitionId] = name
+	}
+	incident = messages.Incident{
+		Id:                  historic.Id,
+		MsgVersion:          3,
+		ExternalTaskId:      historic.ActivityId,
+		ProcessInstanceId:   historic.ProcessInstanceId,
+		ProcessDefinitionId: historic.ProcessDefinitionId,
+		WorkerId:            "process-incident-backfill",
+		ErrorMessage:        historic.IncidentMessage,
+		Time:                created,
+		TenantId:            historic.TenantId,
+		DeploymentName:      name,
+		IncidentType:        historic.IncidentType,
+		ActivityId:          historic.ActivityId,
+		CauseIncidentId:     historic.CauseIncidentId,
+		RootCauseIncidentId: historic.RootCauseIncidentId,
+		JobDefinitionId:     historic.JobDefinitionId,
+		Configuration:       historic.Configuration,
+	}
+	if !historic.Open {
+		incident.Status = messages.IncidentStatusResolved
+		incident.StatusChangedBy = incident.WorkerId
+		incident.StatusChangedAt, _ = time.Parse(messages.CamundaTimeFormat, historic.EndTime)
+	}
+	return incident
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"github.com/SENERGY-Platform/process-incident-api/lib/notification"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"github.com/google/uuid"
+)
+
+// SetNotificationChannel creates the channel if the id is empty and replaces it otherwise;
+// admins may set channels for every tenant, other users only for themselves and process-definitions they own
+func (this *Controller) SetNotificationChannel(token string, channel messages.NotificationChannel) (result messages.NotificationChannel, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return result, err, http.StatusUnauthorized
+	}
+	if !jwtToken.IsAdmin() || channel.TenantId == "" {
+		channel.TenantId = jwtToken.GetUserId()
+	}
+	err = notification.ValidateChannel(this.config, channel)
+	if err != nil {
+		return result, err, http.StatusBadRequest
+	}
+	if channel.ProcessDefinitionId != "" {
+		err, code = this.checkOnIncidentHandlerAccess(jwtToken, channel.ProcessDefinitionId)
+		if err != nil {
+			return result, err, code
+		}
+	}
+	if channel.Id == "" {
+		channel.Id = uuid.NewString()
+	} else {
+		existing, err, code := this.getNotificationChannel(jwtToken, channel.Id)
+		if err != nil && code != http.StatusNotFound {
+			return result, err, code
+		}
+		//keep the secret if it is not changed, because it is not returned by the api
+		if err == nil && channel.Secret == "" && channel.Type == existing.Type && channel.Url == existing.Url {
+			channel.Secret = existing.Secret
+		}
+	}
+	err = this.db.SaveNotificationChannel(channel)
+	if err != nil {
+		return result, err, http.StatusInternalServerError
+	}
+	channel.Secret = ""
+	return channel, nil, http.StatusOK
+}
+
+func (this *Controller) GetNotificationChannel(token string, id string) (channel messages.NotificationChannel, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return channel, err, http.StatusUnauthorized
+	}
+	channel, err, code = this.getNotificationChannel(jwtToken, id)
+	channel.Secret = ""
+	return channel, err, code
+}
+
+// ListNotificationChannels lists all channels for admins and the channels of the requesting user otherwise
+func (this *Controller) ListNotificationChannels(token string, limit int, offset int) (channels []messages.NotificationChannel, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return channels, err, http.StatusUnauthorized
+	}
+	tenantId := ""
+	if !jwtToken.IsAdmin() {
+		tenantId = jwtToken.GetUserId()
+	}
+	channels, err = this.db.ListNotificationChannels(tenantId, limit, offset)
+	if err != nil {
+		return channels, err, http.StatusInternalServerError
+	}
+	for i := range channels {
+		channels[i].Secret = ""
+	}
+	return channels, nil, http.StatusOK
+}
+
+func (this *Controller) DeleteNotificationChannel(token string, id string) (err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return err, http.StatusUnauthorized
+	}
+	_, err, code = this.getNotificationChannel(jwtToken, id)
+	if err != nil {
+		return err, code
+	}
+	err = this.db.DeleteNotificationChannel(id)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+// getNotificationChannel returns the channel if it exists and belongs to the user; admins may access every channel
+func (this *Controller) getNotificationChannel(jwtToken jwt.Token, id string) (channel messages.NotificationChannel, err error, code int) {
+	channel, exists, err := this.db.GetNotificationChannel(id)
+	if err != nil {
+		return channel, err, http.StatusInternalServerError
+	}
+	if !exists {
+		return channel, errors.New("not found"), http.StatusNotFound
+	}
+	if !jwtToken.IsAdmin() && channel.TenantId != jwtToken.GetUserId() {
+		return channel, errors.New("access to notification channel denied"), http.StatusForbidden
+	}
+	return channel, nil, http.StatusOK
+}
+
+// notifyChannels stores the message in the outbox for every notification channel of the incident, to be delivered not before at
+func (this *Controller) notifyChannels(incident messages.Incident, msg notification.Message, at time.Time) {
+	channels, err := this.db.FindIncidentNotificationChannels(incident.TenantId, incident.ProcessDefinitionId)
+	if err != nil {
+		log.Println("ERROR: unable to load notification channels", err)
+		return
+	}
+	now := time.Now()
+	for _, channel := range channels {
+		err = this.db.EnqueueNotification(messages.OutboxEntry{
+			Id:                  uuid.NewString(),
+			ChannelId:           channel.Id,
+			IncidentId:          incident.Id,
+			ProcessDefinitionId: incident.ProcessDefinitionId,
+			ProcessInstanceId:   incident.ProcessInstanceId,
+			DeploymentName:      incident.DeploymentName,
+			Severity:            incident.Severity,
+			UserId:              msg.UserId,
+			Title:               msg.Title,
+			Message:             msg.Message,
+			Topic:               msg.Topic,
+			Status:              messages.OutboxStatusPending,
+			NextAttempt:         at,
+			Created:             now,
+		})
+		if err != nil {
+			log.Println("ERROR: unable to store notification for channel in outbox", channel.Id, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"github.com/google/uuid"
+)
+
+func (this *Controller) AddIncidentComment(token string, incidentId string, comment messages.IncidentComment) (result messages.IncidentComment, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return result, err, http.StatusUnauthorized
+	}
+	if strings.TrimSpace(comment.Text) == "" {
+		return result, errors.New("missing text"), http.StatusBadRequest
+	}
+	incident, exists, err := this.db.GetIncidents(incidentId, jwtToken.GetUserId())
+	if err != nil {
+		log.Printf("ERROR: %+v \n", err) //prints error with stack trace if error is from github.com/pkg/errors
+		return result, errors.New("database error"), http.StatusInternalServerError
+	}
+	if !exists {
+		return result, errors.New("not found"), http.StatusNotFound
+	}
+	result = messages.IncidentComment{
+		Id:                  uuid.NewString(),
+		IncidentId:          incident.Id,
+		ProcessInstanceId:   incident.ProcessInstanceId,
+		ProcessDefinitionId: incident.ProcessDefinitionId,
+		TenantId:            incident.TenantId,
+		UserId:              jwtToken.GetUserId(),
+		Text:                comment.Text,
+		Time:                time.Now(),
+	}
+	err = this.db.SaveIncidentComment(result)
+	if err != nil {
+		log.Printf("ERROR: %+v \n", err) //prints error with stack trace if error is from github.com/pkg/errors
+		return result, errors.New("database error"), http.StatusInternalServerError
+	}
+	return result, nil, http.StatusOK
+}
+
+func (this *Controller) ListIncidentComments(token string, incidentId string) (comments []messages.IncidentComment, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return comments, err, http.StatusUnauthorized
+	}
+	_, exists, err := this.db.GetIncidents(incidentId, jwtToken.GetUserId())
+	if err != nil {
+		log.Printf("ERROR: %+v \n", err) //prints error with stack trace if error is from github.com/pkg/errors
+		return comments, errors.New("database error"), http.StatusInternalServerError
+	}
+	if !exists {
+		return comments, errors.New("not found"), http.StatusNotFound
+	}
+	comments, err = this.db.ListIncidentComments(incidentId, jwtToken.GetUserId())
+	if err != nil {
+		log.Printf("ERROR: %+v \n", err) //prints error with stack trace if error is from github.com/pkg/errors
+		return comments, errors.New("database error"), http.StatusInternalServerError
+	}
+	return comments, nil, http.StatusOK
+}
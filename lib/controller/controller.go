@@ -21,13 +21,17 @@ import (
 	developerNotifications "github.com/SENERGY-Platform/developer-notifications/pkg/client"
 	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
 	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
+	"github.com/SENERGY-Platform/process-incident-api/lib/notification"
 	"github.com/SENERGY-Platform/service-commons/pkg/cache"
+	"github.com/SENERGY-Platform/service-commons/pkg/cache/memcached"
 	"log/slog"
 	"os"
 	"runtime/debug"
+	"time"
 )
 
 type Controller struct {
+	ctx                   context.Context
 	config                configuration.Config
 	db                    interfaces.Database
 	camunda               interfaces.Camunda
@@ -36,6 +40,10 @@ type Controller struct {
 	metrics               Metric
 	devNotifications      developerNotifications.Client
 	logger                *slog.Logger
+	deduplicationWindow   time.Duration
+	backfillJobRetention  time.Duration
+	severityPatterns      patternCache
+	templates             *notification.Templates
 }
 
 type Metric interface {
@@ -47,11 +55,32 @@ func New(ctx context.Context, config configuration.Config, db interfaces.Databas
 	if info, ok := debug.ReadBuildInfo(); ok {
 		logger = logger.With("go-module", info.Path)
 	}
-	c, err := cache.New(cache.Config{}) //if the worker is scaled, the l2 must be configured with a shared memcached
+	//if the worker is scaled, shared_incident_deduplication should be used or the l2 must be configured with a shared memcached
+	cacheConfig := cache.Config{}
+	if len(config.HandledIncidentsMemcachedUrls) > 0 {
+		cacheConfig.L2Provider = memcached.NewProvider(10, 200*time.Millisecond, config.HandledIncidentsMemcachedUrls...)
+	}
+	c, err := cache.New(cacheConfig)
+	if err != nil {
+		return nil, err
+	}
+	deduplicationWindow, err := parseDeduplicationWindow(config.IncidentDeduplicationWindow)
+	if err != nil {
+		return nil, err
+	}
+	err = validateDefaultSeverity(config.IncidentDefaultSeverity)
+	if err != nil {
+		return nil, err
+	}
+	backfillJobRetention, err := parseBackfillJobRetention(config.IncidentBackfillJobRetention)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := notification.LoadTemplates(config)
 	if err != nil {
 		return nil, err
 	}
-	ctrl = &Controller{config: config, camunda: camunda, db: db, metrics: m, logger: logger, handledIncidentsCache: c}
+	ctrl = &Controller{ctx: ctx, config: config, camunda: camunda, db: db, metrics: m, logger: logger, handledIncidentsCache: c, deduplicationWindow: deduplicationWindow, backfillJobRetention: backfillJobRetention, templates: templates}
 	if config.DeveloperNotificationUrl != "" && config.DeveloperNotificationUrl != "-" {
 		ctrl.devNotifications = developerNotifications.New(config.DeveloperNotificationUrl)
 	}
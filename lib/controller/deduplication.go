@@ -0,0 +1,95 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"github.com/SENERGY-Platform/service-commons/pkg/cache"
+	"github.com/google/uuid"
+)
+
+const DefaultDeduplicationWindow = 5 * time.Minute
+
+// parseDeduplicationWindow returns DefaultDeduplicationWindow for empty strings; "-" disables deduplication
+func parseDeduplicationWindow(str string) (time.Duration, error) {
+	switch str {
+	case "":
+		return DefaultDeduplicationWindow, nil
+	case "-":
+		return 0, nil
+	}
+	window, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid deduplication window: %w", err)
+	}
+	if window < 0 {
+		return 0, fmt.Errorf("invalid deduplication window: may not be negative")
+	}
+	return window, nil
+}
+
+// deduplicate calls handle if no other incident has been handled for the topic within the window.
+// handledIncidentId is the id of the incident handled for the topic, which may be the incidentId if handled is true
+func (this *Controller) deduplicate(topic string, incidentId string, window time.Duration, handle func() error) (handled bool, handledIncidentId string, err error) {
+	if this.config.SharedIncidentDeduplication {
+		return this.deduplicateWithLease(topic, incidentId, window, handle)
+	}
+	//use the cache.Use method to do incident handling, only if the topic is not found in cache
+	handledIncidentId, err = cache.Use[string](this.handledIncidentsCache, topic, func() (string, error) {
+		handled = true
+		return incidentId, handle()
+	}, cache.NoValidation, window)
+	return handled, handledIncidentId, err
+}
+
+// deduplicateWithLease uses a lease in the database to ensure that only one instance of the worker handles incidents of the topic
+func (this *Controller) deduplicateWithLease(topic string, incidentId string, window time.Duration, handle func() error) (handled bool, handledIncidentId string, err error) {
+	name := "incident:" + topic
+	owner := uuid.NewString()
+	acquired, current, err := this.db.TryLease(name, owner, incidentId, window)
+	if err != nil {
+		return false, "", err
+	}
+	if !acquired {
+		return false, current.Value, nil
+	}
+	err = handle()
+	if err != nil {
+		//allow a retry of the failed incident handling
+		releaseErr := this.db.ReleaseLease(name, owner)
+		if releaseErr != nil {
+			this.logger.Error("unable to release incident lease", "snrgy-log-type", "warning", "error", releaseErr.Error(), "lease", name)
+		}
+		return true, incidentId, err
+	}
+	return true, incidentId, nil
+}
+
+func (this *Controller) getDeduplicationWindow(handler messages.OnIncident, registeredHandling bool) time.Duration {
+	if !registeredHandling || handler.DeduplicationWindow == "" {
+		return this.deduplicationWindow
+	}
+	window, err := parseDeduplicationWindow(handler.DeduplicationWindow)
+	if err != nil {
+		this.logger.Warn("invalid deduplication window in on-incident handler, use default", "snrgy-log-type", "warning", "error", err.Error(), "process-definition-id", handler.ProcessDefinitionId)
+		return this.deduplicationWindow
+	}
+	return window
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"github.com/SENERGY-Platform/process-incident-api/lib/notification"
+)
+
+const DefaultDigestInterval = 15 * time.Minute
+const DefaultRateLimitWindow = time.Hour
+
+// notifyIncident notifies the incident immediately, if no digest is requested and the tenant is within its rate limit;
+// otherwise the incident is added to the digest of the tenant.
+// during quiet hours of the tenant, notifications are suppressed or delayed by the severity of the incident
+func (this *Controller) notifyIncident(incident messages.Incident, settings messages.TenantSettings, handling messages.OnIncident, digest bool, restart bool) {
+	now := time.Now()
+	at, suppressed := this.getNotificationTime(incident, settings, now)
+	if suppressed {
+		return
+	}
+	if !digest && settings.DigestInterval == "" {
+		limited, err := this.useNotificationRateLimit(incident.TenantId, settings)
+		if err != nil {
+			log.Println("WARNING: unable to check notification rate limit, notify immediately", incident.TenantId, err)
+		}
+		if !limited {
+			data := notification.TemplateData{Incident: incident}
+			if restart {
+				data.RestartMode = getRestartMode(handling)
+				_, data.RestartNotice = this.templates.Render(notification.TemplateRestartNotice, settings, data)
+			}
+			title, message := this.templates.Render(notification.TemplateIncident, settings, data)
+			msg := notification.Message{
+				UserId:  incident.TenantId,
+				Title:   title,
+				Message: message,
+				Topic:   notification.Topic,
+			}
+			this.notifyAt(msg, at)
+			this.notifyChannels(incident, msg, at)
+			return
+		}
+	}
+	interval, intervalStr := this.getDigestInterval(settings)
+	//a delayed incident may not be sent with a digest before the end of the quiet hours
+	due := now.Add(interval)
+	notBefore := time.Time{}
+	if at.After(now) {
+		notBefore = at
+	}
+	if at.After(due) {
+		due = at
+	}
+	err := this.db.AddToNotificationDigest(incident.TenantId, messages.DigestIncident{
+		Id:                  incident.Id,
+		ProcessDefinitionId: incident.ProcessDefinitionId,
+		ProcessInstanceId:   incident.ProcessInstanceId,
+		DeploymentName:      incident.DeploymentName,
+		ErrorMessage:        incident.ErrorMessage,
+		Time:                incident.Time,
+	}, due, notBefore, intervalStr)
+	if err != nil {
+		log.Println("ERROR: unable to add incident to notification digest", incident.TenantId, incident.Id, err)
+	}
+}
+
+// getNotificationTime returns the time a notification of the incident may be sent, by the quiet hours of the tenant and the severity of the incident
+func (this *Controller) getNotificationTime(incident messages.Incident, settings messages.TenantSettings, now time.Time) (at time.Time, suppressed bool) {
+	action, end, err := getQuietHoursAction(settings, incident.Severity, now)
+	if err != nil {
+		log.Println("WARNING: unable to check quiet hours, notify immediately", incident.TenantId, err)
+	}
+	switch action {
+	case messages.QuietHoursActionSuppress:
+		this.logger.Info("notification suppressed by quiet hours", "snrgy-log-type", "process-incident", "user", incident.TenantId, "process-definition-id", incident.ProcessDefinitionId, "process-instance-id", incident.ProcessInstanceId, "severity", incident.Severity)
+		return now, true
+	case messages.QuietHoursActionDelay:
+		return end, false
+	}
+	return now, false
+}
+
+// useNotificationRateLimit counts the notification and returns true if the tenant exceeded its rate limit
+func (this *Controller) useNotificationRateLimit(tenantId string, settings messages.TenantSettings) (limited bool, err error) {
+	limit := settings.RateLimit
+	if limit == 0 {
+		limit = this.config.NotificationRateLimit
+	}
+	if limit <= 0 {
+		return false, nil
+	}
+	window := DefaultRateLimitWindow
+	if settings.RateLimitWindow != "" {
+		window, err = time.ParseDuration(settings.RateLimitWindow)
+	} else if this.config.NotificationRateLimitWindow != "" {
+		window, err = time.ParseDuration(this.config.NotificationRateLimitWindow)
+	}
+	if err != nil {
+		return false, err
+	}
+	count, err := this.db.IncrementNotificationRateCount(tenantId, time.Now(), window)
+	if err != nil {
+		return false, err
+	}
+	return count > limit, nil
+}
+
+func (this *Controller) getDigestInterval(settings messages.TenantSettings) (interval time.Duration, intervalStr string) {
+	for _, candidate := range []string{settings.DigestInterval, this.config.NotificationDigestInterval} {
+		if candidate == "" {
+			continue
+		}
+		interval, err := time.ParseDuration(candidate)
+		if err == nil && interval > 0 {
+			return interval, candidate
+		}
+		log.Println("WARNING: invalid digest interval", candidate, err)
+	}
+	return DefaultDigestInterval, DefaultDigestInterval.String()
+}
+
+func validateNotificationSettings(settings messages.TenantSettings) error {
+	if settings.DigestInterval != "" {
+		interval, err := time.ParseDuration(settings.DigestInterval)
+		if err != nil {
+			return errors.New("invalid digest_interval: " + err.Error())
+		}
+		if interval <= 0 {
+			return errors.New("digest_interval must be positive")
+		}
+	}
+	if settings.RateLimitWindow != "" {
+		window, err := time.ParseDuration(settings.RateLimitWindow)
+		if err != nil {
+			return errors.New("invalid rate_limit_window: " + err.Error())
+		}
+		if window <= 0 {
+			return errors.New("rate_limit_window must be positive")
+		}
+	}
+	if settings.RateLimit < -1 {
+		return errors.New("rate_limit must be -1, 0 or positive")
+	}
+	return nil
+}
+
+// StartDigests sends due digests in the notification dispatch interval
+func (this *Controller) StartDigests(ctx context.Context) error {
+	if this.config.NotificationDispatchInterval == "" || this.config.NotificationDispatchInterval == "-" {
+		return nil
+	}
+	interval, err := time.ParseDuration(this.config.NotificationDispatchInterval)
+	if err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				this.SendDueDigests()
+			}
+		}
+	}()
+	return nil
+}
+
+// SendDueDigests notifies all due digests; digests are sent to the platform notifier and the channels of the tenant, that are not limited to a process-definition
+func (this *Controller) SendDueDigests() {
+	for {
+		digest, found, err := this.db.TakeDueNotificationDigest(time.Now())
+		if err != nil {
+			log.Println("ERROR: unable to load due notification digest", err)
+			return
+		}
+		if !found {
+			return
+		}
+		settings := this.getTenantSettings(digest.TenantId)
+		title, message := this.templates.Render(notification.TemplateDigest, settings, notification.TemplateData{Digest: digest})
+		msg := notification.Message{
+			UserId:  digest.TenantId,
+			Title:   title,
+			Message: message,
+			Topic:   notification.Topic,
+		}
+		now := time.Now()
+		this.notifyAt(msg, now)
+		this.notifyChannels(messages.Incident{TenantId: digest.TenantId}, msg, now)
+	}
+}
@@ -22,9 +22,23 @@ import (
 	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
 	"log"
 	"net/http"
+	"slices"
+	"time"
 )
 
+var incidentStatuses = []string{messages.IncidentStatusOpen, messages.IncidentStatusAcknowledged, messages.IncidentStatusResolved}
+
+// GetIncident returns the incident without large details like the stack trace
 func (this *Controller) GetIncident(token string, id string) (incident messages.IncidentMessage, err error, errCode int) {
+	return this.getIncident(token, id, false)
+}
+
+// GetIncidentDetails returns the incident including large details like the stack trace
+func (this *Controller) GetIncidentDetails(token string, id string) (incident messages.IncidentMessage, err error, errCode int) {
+	return this.getIncident(token, id, true)
+}
+
+func (this *Controller) getIncident(token string, id string, details bool) (incident messages.IncidentMessage, err error, errCode int) {
 	jwtToken, err := jwt.Parse(token)
 	if err != nil {
 		return incident, err, http.StatusUnauthorized
@@ -37,19 +51,81 @@ func (this *Controller) GetIncident(token string, id string) (incident messages.
 	if !exists {
 		return incident, errors.New("not found"), http.StatusNotFound
 	}
+	if !details {
+		incident.StackTrace = ""
+	}
 	return incident, nil, http.StatusOK
 }
 
-func (this *Controller) FindIncidents(token string, externalTaskId string, processDefinitionId string, processInstanceId string, limit int, offset int, sortBy string, asc bool) (incidents []messages.IncidentMessage, err error, errCode int) {
+func (this *Controller) FindIncidents(token string, options messages.FindIncidentsOptions) (incidents []messages.IncidentMessage, nextCursor string, err error, errCode int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return incidents, nextCursor, err, http.StatusUnauthorized
+	}
+	err = validateIncidentFilter(options.IncidentFilter)
+	if err != nil {
+		return incidents, nextCursor, err, http.StatusBadRequest
+	}
+	var parsedCursor *messages.IncidentCursor
+	if options.Cursor != "" {
+		temp, err := messages.ParseIncidentCursor(options.Cursor)
+		if err != nil {
+			return incidents, nextCursor, err, http.StatusBadRequest
+		}
+		if temp.SortBy != options.SortBy || temp.Asc != options.Asc {
+			return incidents, nextCursor, errors.New("cursor does not match sort"), http.StatusBadRequest
+		}
+		parsedCursor = &temp
+	}
+	incidents, err = this.db.FindIncidents(options.IncidentFilter, parsedCursor, options.Limit, options.Offset, options.SortBy, options.Asc, jwtToken.GetUserId())
+	if err != nil {
+		log.Printf("ERROR: %+v \n", err) //prints error with stack trace if error is from github.com/pkg/errors
+		err = errors.New("database error")
+		return incidents, nextCursor, err, http.StatusInternalServerError
+	}
+	return incidents, messages.NextIncidentCursor(incidents, options), nil, http.StatusOK
+}
+
+func (this *Controller) CountIncidents(token string, filter messages.IncidentFilter) (count int64, err error, errCode int) {
 	jwtToken, err := jwt.Parse(token)
 	if err != nil {
-		return incidents, err, http.StatusUnauthorized
+		return count, err, http.StatusUnauthorized
+	}
+	err = validateIncidentFilter(filter)
+	if err != nil {
+		return count, err, http.StatusBadRequest
 	}
-	incidents, err = this.db.FindIncidents(externalTaskId, processDefinitionId, processInstanceId, limit, offset, sortBy, asc, jwtToken.GetUserId())
+	count, err = this.db.CountIncidents(filter, jwtToken.GetUserId())
 	if err != nil {
 		log.Printf("ERROR: %+v \n", err) //prints error with stack trace if error is from github.com/pkg/errors
 		err = errors.New("database error")
-		return incidents, err, http.StatusInternalServerError
+		return count, err, http.StatusInternalServerError
+	}
+	return count, nil, http.StatusOK
+}
+
+func (this *Controller) SetIncidentStatus(token string, id string, status string) (err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return err, http.StatusUnauthorized
+	}
+	if !slices.Contains(incidentStatuses, status) {
+		return errors.New("unknown status"), http.StatusBadRequest
+	}
+	exists, err := this.db.SetIncidentStatus(id, jwtToken.GetUserId(), status, jwtToken.GetUserId(), time.Now())
+	if err != nil {
+		log.Printf("ERROR: %+v \n", err) //prints error with stack trace if error is from github.com/pkg/errors
+		return errors.New("database error"), http.StatusInternalServerError
+	}
+	if !exists {
+		return errors.New("not found"), http.StatusNotFound
+	}
+	return nil, http.StatusOK
+}
+
+func validateIncidentFilter(filter messages.IncidentFilter) error {
+	if filter.Status != "" && !slices.Contains(incidentStatuses, filter.Status) {
+		return errors.New("unknown status")
 	}
-	return incidents, nil, http.StatusOK
+	return nil
 }
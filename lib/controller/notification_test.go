@@ -0,0 +1,144 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+)
+
+func TestIsQuietTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	overnight := messages.QuietHours{Start: "22:00", End: "07:00", TimeZone: "Europe/Berlin"}
+	daytime := messages.QuietHours{Start: "12:00", End: "13:30", TimeZone: "UTC"}
+	tests := []struct {
+		name       string
+		quietHours messages.QuietHours
+		now        time.Time
+		quiet      bool
+		end        time.Time
+	}{
+		{"before midnight", overnight, time.Date(2025, 3, 1, 23, 0, 0, 0, berlin), true, time.Date(2025, 3, 2, 7, 0, 0, 0, berlin)},
+		{"after midnight", overnight, time.Date(2025, 3, 2, 6, 59, 0, 0, berlin), true, time.Date(2025, 3, 2, 7, 0, 0, 0, berlin)},
+		{"other time zone", overnight, time.Date(2025, 3, 1, 21, 30, 0, 0, time.UTC), true, time.Date(2025, 3, 2, 7, 0, 0, 0, berlin)},
+		{"end is excluded", overnight, time.Date(2025, 3, 2, 7, 0, 0, 0, berlin), false, time.Time{}},
+		{"outside overnight", overnight, time.Date(2025, 3, 2, 12, 0, 0, 0, berlin), false, time.Time{}},
+		{"start is included", daytime, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), true, time.Date(2025, 3, 1, 13, 30, 0, 0, time.UTC)},
+		{"outside daytime", daytime, time.Date(2025, 3, 1, 14, 0, 0, 0, time.UTC), false, time.Time{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quiet, end, err := isQuietTime(test.quietHours, test.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if quiet != test.quiet || !end.Equal(test.end) {
+				t.Error(quiet, end)
+			}
+		})
+	}
+	_, _, err = isQuietTime(messages.QuietHours{Start: "22:00", End: "07:00", TimeZone: "Foo/Bar"}, time.Now())
+	if err == nil {
+		t.Error("expected error for unknown time zone")
+	}
+}
+
+func TestGetSeverity(t *testing.T) {
+	settings := messages.TenantSettings{SeverityRules: []messages.SeverityRule{
+		{ErrorMessagePattern: "(", Severity: messages.SeverityCritical},
+		{ErrorMessagePattern: "^fatal", Severity: messages.SeverityCritical},
+		{ProcessDefinitionId: "pd-low", Severity: messages.SeverityLow},
+		{ProcessDefinitionId: "pd-high", IncidentType: messages.CamundaIncidentTypeFailedJob, Severity: messages.SeverityHigh},
+	}}
+	tests := []struct {
+		name            string
+		defaultSeverity string
+		incident        messages.Incident
+		severity        string
+	}{
+		{"first matching rule", "", messages.Incident{ProcessDefinitionId: "pd-low", ErrorMessage: "fatal error"}, messages.SeverityCritical},
+		{"process definition rule", "", messages.Incident{ProcessDefinitionId: "pd-low", ErrorMessage: "error"}, messages.SeverityLow},
+		{"incident type rule", "", messages.Incident{ProcessDefinitionId: "pd-high", IncidentType: messages.CamundaIncidentTypeFailedJob}, messages.SeverityHigh},
+		{"incident type mismatch", "", messages.Incident{ProcessDefinitionId: "pd-high"}, messages.SeverityMedium},
+		{"configured default", messages.SeverityLow, messages.Incident{ProcessDefinitionId: "pd-other"}, messages.SeverityLow},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := &Controller{config: configuration.Config{IncidentDefaultSeverity: test.defaultSeverity}}
+			if severity := ctrl.getSeverity(settings, test.incident); severity != test.severity {
+				t.Error(severity)
+			}
+		})
+	}
+}
+
+// rateLimitDatabase counts notifications per tenant, ignoring the window
+type rateLimitDatabase struct {
+	interfaces.Database
+	counts map[string]int64
+}
+
+func (this *rateLimitDatabase) IncrementNotificationRateCount(tenantId string, now time.Time, window time.Duration) (count int64, err error) {
+	this.counts[tenantId] = this.counts[tenantId] + 1
+	return this.counts[tenantId], nil
+}
+
+func TestUseNotificationRateLimit(t *testing.T) {
+	db := &rateLimitDatabase{counts: map[string]int64{}}
+	ctrl := &Controller{db: db, config: configuration.Config{NotificationRateLimit: 2, NotificationRateLimitWindow: "1h"}}
+
+	check := func(t *testing.T, tenantId string, settings messages.TenantSettings, expected []bool) {
+		for i, expectedLimited := range expected {
+			limited, err := ctrl.useNotificationRateLimit(tenantId, settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if limited != expectedLimited {
+				t.Error(i, limited)
+			}
+		}
+	}
+
+	t.Run("configured limit", func(t *testing.T) {
+		check(t, "a", messages.TenantSettings{TenantId: "a"}, []bool{false, false, true})
+	})
+	t.Run("counted per tenant", func(t *testing.T) {
+		check(t, "b", messages.TenantSettings{TenantId: "b"}, []bool{false, false, true})
+	})
+	t.Run("tenant limit", func(t *testing.T) {
+		check(t, "c", messages.TenantSettings{TenantId: "c", RateLimit: 1}, []bool{false, true})
+	})
+	t.Run("disabled by tenant", func(t *testing.T) {
+		check(t, "d", messages.TenantSettings{TenantId: "d", RateLimit: -1}, []bool{false, false, false})
+		if db.counts["d"] != 0 {
+			t.Error(db.counts["d"])
+		}
+	})
+	t.Run("invalid window", func(t *testing.T) {
+		_, err := ctrl.useNotificationRateLimit("e", messages.TenantSettings{TenantId: "e", RateLimitWindow: "foo"})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"github.com/SENERGY-Platform/process-incident-api/lib/notification"
+	"github.com/google/uuid"
+)
+
+const DefaultRestartWindow = time.Hour
+const DefaultMaxRestartBackoff = time.Hour
+
+// PendingRestartLock is the time a claimed restart is hidden from other instances; if the claiming instance stops, the restart is run after this time
+const PendingRestartLock = time.Minute
+
+func ValidateOnIncident(handler messages.OnIncident) error {
+	if handler.ProcessDefinitionId == "" {
+		return errors.New("missing process_definition_id")
+	}
+	if handler.MaxRestarts < 0 {
+		return errors.New("max_restarts may not be negative")
+	}
+	if handler.RestartRetries < 0 {
+		return errors.New("restart_retries may not be negative")
+	}
+	switch handler.RestartMode {
+	case "", messages.OnIncidentRestartModeProcess, messages.OnIncidentRestartModeRetry, messages.OnIncidentRestartModeActivity:
+	default:
+		return errors.New("unknown restart_mode")
+	}
+	if handler.KeepAlive && handler.Restart && getRestartMode(handler) == messages.OnIncidentRestartModeProcess {
+		return errors.New("keep_alive may not be combined with restart_mode " + messages.OnIncidentRestartModeProcess)
+	}
+	if handler.DeduplicationWindow != "" {
+		_, err := parseDeduplicationWindow(handler.DeduplicationWindow)
+		if err != nil {
+			return err
+		}
+	}
+	_, _, _, err := getRestartDurations(handler)
+	return err
+}
+
+func getRestartDurations(handler messages.OnIncident) (window time.Duration, backoff time.Duration, maxBackoff time.Duration, err error) {
+	window = DefaultRestartWindow
+	maxBackoff = DefaultMaxRestartBackoff
+	if handler.RestartWindow != "" {
+		window, err = time.ParseDuration(handler.RestartWindow)
+		if err != nil {
+			return window, backoff, maxBackoff, fmt.Errorf("invalid restart_window: %w", err)
+		}
+	}
+	if handler.RestartBackoff != "" {
+		backoff, err = time.ParseDuration(handler.RestartBackoff)
+		if err != nil {
+			return window, backoff, maxBackoff, fmt.Errorf("invalid restart_backoff: %w", err)
+		}
+	}
+	if handler.MaxRestartBackoff != "" {
+		maxBackoff, err = time.ParseDuration(handler.MaxRestartBackoff)
+		if err != nil {
+			return window, backoff, maxBackoff, fmt.Errorf("invalid max_restart_backoff: %w", err)
+		}
+	}
+	return window, backoff, maxBackoff, nil
+}
+
+func getRestartMode(handler messages.OnIncident) string {
+	if handler.RestartMode == "" {
+		return messages.OnIncidentRestartModeProcess
+	}
+	return handler.RestartMode
+}
+
+// getRestartDelay returns backoff doubled for every restart after the first one in the current window, limited by maxBackoff
+func getRestartDelay(backoff time.Duration, maxBackoff time.Duration, restartCount int64) time.Duration {
+	if backoff <= 0 {
+		return 0
+	}
+	delay := backoff
+	for i := int64(1); i < restartCount && delay < maxBackoff; i++ {
+		delay = delay * 2
+	}
+	if delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
+
+// useRestartBudget counts the restart in the database and decides if and when the process may be restarted.
+// if the budget of the handler is exhausted, restarts get disabled for the process-definition and the user is notified
+func (this *Controller) useRestartBudget(handler messages.OnIncident, incident messages.Incident) (restart bool, delay time.Duration, err error) {
+	window, backoff, maxBackoff, err := getRestartDurations(handler)
+	if err != nil {
+		return false, 0, err
+	}
+	state, exists, err := this.db.IncrementOnIncidentRestartCount(handler.ProcessDefinitionId, time.Now(), window)
+	if err != nil {
+		return false, 0, err
+	}
+	if !exists {
+		return false, 0, nil
+	}
+	if handler.MaxRestarts > 0 && state.RestartCount > handler.MaxRestarts {
+		err = this.db.DisableOnIncidentRestart(handler.ProcessDefinitionId)
+		if err != nil {
+			return false, 0, err
+		}
+		this.logger.Warn("restart budget exhausted, disable restart", "snrgy-log-type", "process-incident", "user", incident.TenantId, "deployment-name", incident.DeploymentName, "process-definition-id", incident.ProcessDefinitionId, "max-restarts", handler.MaxRestarts, "restart-window", window.String())
+		this.notifyTenant(notification.TemplateRestartDisabled, notification.TemplateData{
+			Incident:      incident,
+			MaxRestarts:   handler.MaxRestarts,
+			RestartWindow: window.String(),
+		})
+		return false, 0, nil
+	}
+	return true, getRestartDelay(backoff, maxBackoff, state.RestartCount), nil
+}
+
+// getStartVariables loads the start-parameters of the failed process-instance; the caller skips the restart and notifies the user if they are not available
+func (this *Controller) getStartVariables(incident messages.Incident) (variables map[string]messages.CamundaVariable, err error) {
+	variables, err = this.camunda.GetStartVariables(incident.ProcessInstanceId, incident.ProcessDefinitionId, incident.TenantId)
+	if err != nil {
+		this.logger.Error("unable to get start variables, skip restart", "snrgy-log-type", "process-incident", "error", err.Error(), "user", incident.TenantId, "deployment-name", incident.DeploymentName, "process-definition-id", incident.ProcessDefinitionId, "process-instance-id", incident.ProcessInstanceId)
+	}
+	return variables, err
+}
+
+// notifyRestartFailed notifies the tenant of the incident, that the restart with restartMode failed
+func (this *Controller) notifyRestartFailed(incident messages.Incident, restartMode string, errMsg string) {
+	this.notifyTenant(notification.TemplateRestartFailed, notification.TemplateData{
+		Incident:    incident,
+		RestartMode: restartMode,
+		Error:       errMsg,
+	})
+}
+
+// restart handles the incident according to the restart mode of the handler
+func (this *Controller) restart(handler messages.OnIncident, incident messages.Incident, variables map[string]messages.CamundaVariable) {
+	var err error
+	switch getRestartMode(handler) {
+	case messages.OnIncidentRestartModeRetry:
+		retries := handler.RestartRetries
+		if retries == 0 {
+			retries = 1
+		}
+		err = this.camunda.RetryIncident(incident, retries, incident.TenantId)
+	case messages.OnIncidentRestartModeActivity:
+		err = this.camunda.RestartIncidentActivity(incident, incident.TenantId)
+	default:
+		this.restartProcess(incident, variables)
+		return
+	}
+	if err != nil {
+		this.logger.Error("unable to restart failed activity", "snrgy-log-type", "process-incident", "error", err.Error(), "user", incident.TenantId, "deployment-name", incident.DeploymentName, "process-definition-id", incident.ProcessDefinitionId, "process-instance-id", incident.ProcessInstanceId, "restart-mode", handler.RestartMode)
+		this.notifyRestartFailed(incident, getRestartMode(handler), err.Error())
+	}
+}
+
+// restartProcess starts a new instance of the incidents process-definition; if variables is nil, the process is started without start-parameters
+func (this *Controller) restartProcess(incident messages.Incident, variables map[string]messages.CamundaVariable) {
+	var err error
+	if variables != nil {
+		err = this.camunda.StartProcessWithVariables(incident.ProcessDefinitionId, incident.BusinessKey, variables, incident.TenantId)
+	} else if incident.BusinessKey != "" {
+		err = this.camunda.StartProcessWithBusinessKey(incident.ProcessDefinitionId, incident.BusinessKey, incident.TenantId)
+	} else {
+		err = this.camunda.StartProcess(incident.ProcessDefinitionId, incident.TenantId)
+	}
+	if err != nil {
+		this.logger.Error("unable to restart process", "snrgy-log-type", "process-incident", "error", err.Error(), "user", incident.TenantId, "deployment-name", incident.DeploymentName, "process-definition-id", incident.ProcessDefinitionId, "process-instance-id", incident.ProcessInstanceId)
+		this.notifyRestartFailed(incident, messages.OnIncidentRestartModeProcess, err.Error())
+	}
+}
+
+// scheduleRestart stores a delayed restart, to be run by RunDueRestarts of any instance after due
+func (this *Controller) scheduleRestart(handler messages.OnIncident, incident messages.Incident, due time.Time) {
+	err := this.db.SavePendingRestart(messages.PendingRestart{
+		Id:        uuid.NewString(),
+		Due:       due,
+		HandlerId: handler.ProcessDefinitionId,
+		Incident:  incident,
+	})
+	if err != nil {
+		this.logger.Error("unable to schedule restart", "snrgy-log-type", "process-incident", "error", err.Error(), "user", incident.TenantId, "deployment-name", incident.DeploymentName, "process-definition-id", incident.ProcessDefinitionId, "process-instance-id", incident.ProcessInstanceId)
+		this.notifyRestartFailed(incident, getRestartMode(handler), "unable to schedule restart: "+err.Error())
+	}
+}
+
+// StartPendingRestarts runs due delayed restarts in the incident restart check interval
+func (this *Controller) StartPendingRestarts(ctx context.Context) error {
+	if this.config.IncidentRestartCheckInterval == "" || this.config.IncidentRestartCheckInterval == "-" {
+		return nil
+	}
+	interval, err := time.ParseDuration(this.config.IncidentRestartCheckInterval)
+	if err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				this.RunDueRestarts()
+			}
+		}
+	}()
+	return nil
+}
+
+// RunDueRestarts runs all delayed restarts that are due; the handler and the start-parameters are loaded when the restart is run.
+// restarts of removed handlers or handlers with disabled restart (e.g. by an exhausted restart budget) are dropped
+func (this *Controller) RunDueRestarts() {
+	for {
+		now := time.Now()
+		pending, found, err := this.db.ClaimPendingRestart(now, now.Add(PendingRestartLock))
+		if err != nil {
+			log.Println("ERROR: unable to load due restart", err)
+			return
+		}
+		if !found {
+			return
+		}
+		handler, exists, err := this.db.GetOnIncident(pending.HandlerId)
+		if err != nil {
+			//the restart is claimed again after PendingRestartLock
+			log.Println("ERROR: unable to load handler of pending restart", pending.Id, pending.HandlerId, err)
+			return
+		}
+		if exists && handler.Restart {
+			this.runPendingRestart(handler, pending.Incident)
+		} else {
+			this.logger.Info("handler removed or restart disabled, drop pending restart", "snrgy-log-type", "process-incident", "user", pending.Incident.TenantId, "process-definition-id", pending.Incident.ProcessDefinitionId, "process-instance-id", pending.Incident.ProcessInstanceId)
+		}
+		err = this.db.RemovePendingRestart(pending.Id)
+		if err != nil {
+			log.Println("ERROR: unable to remove pending restart", pending.Id, err)
+			return
+		}
+	}
+}
+
+func (this *Controller) runPendingRestart(handler messages.OnIncident, incident messages.Incident) {
+	var variables map[string]messages.CamundaVariable
+	if getRestartMode(handler) == messages.OnIncidentRestartModeProcess && handler.RestartWithStartParameters {
+		var err error
+		variables, err = this.getStartVariables(incident)
+		if err != nil {
+			this.notifyRestartFailed(incident, messages.OnIncidentRestartModeProcess, "unable to load start-parameters: "+err.Error())
+			return
+		}
+	}
+	this.restart(handler, incident, variables)
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+)
+
+var severities = []string{messages.SeverityLow, messages.SeverityMedium, messages.SeverityHigh, messages.SeverityCritical}
+
+const quietHoursTimeFormat = "15:04"
+
+// maxCachedSeverityPatterns limits the memory of patterns, that are no longer used by any tenant
+const maxCachedSeverityPatterns = 1000
+
+// patternCache holds the compiled error_message_pattern of severity rules, so that they are not compiled for every incident
+type patternCache struct {
+	mux      sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+func (this *patternCache) compile(pattern string) (*regexp.Regexp, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if result, ok := this.patterns[pattern]; ok {
+		return result, nil
+	}
+	result, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if this.patterns == nil || len(this.patterns) >= maxCachedSeverityPatterns {
+		this.patterns = map[string]*regexp.Regexp{}
+	}
+	this.patterns[pattern] = result
+	return result, nil
+}
+
+func validateDefaultSeverity(severity string) error {
+	if severity != "" && !slices.Contains(severities, severity) {
+		return errors.New("invalid incident_default_severity: unknown severity " + severity)
+	}
+	return nil
+}
+
+// getSeverity returns the severity of the first matching rule of the tenant or the configured default severity
+func (this *Controller) getSeverity(settings messages.TenantSettings, incident messages.Incident) string {
+	for _, rule := range settings.SeverityRules {
+		match, err := this.matchSeverityRule(rule, incident)
+		if err != nil {
+			log.Println("WARNING: unable to match severity rule", settings.TenantId, err)
+			continue
+		}
+		if match {
+			return rule.Severity
+		}
+	}
+	if this.config.IncidentDefaultSeverity != "" {
+		return this.config.IncidentDefaultSeverity
+	}
+	return messages.SeverityMedium
+}
+
+func (this *Controller) matchSeverityRule(rule messages.SeverityRule, incident messages.Incident) (bool, error) {
+	if rule.ProcessDefinitionId != "" && rule.ProcessDefinitionId != incident.ProcessDefinitionId {
+		return false, nil
+	}
+	if rule.IncidentType != "" && rule.IncidentType != incident.IncidentType {
+		return false, nil
+	}
+	if rule.ErrorMessagePattern != "" {
+		pattern, err := this.severityPatterns.compile(rule.ErrorMessagePattern)
+		if err != nil {
+			return false, err
+		}
+		return pattern.MatchString(incident.ErrorMessage), nil
+	}
+	return true, nil
+}
+
+// getQuietHoursAction returns the action for notifications of the severity at now and the end of the current quiet hours;
+// the action is empty if the notification should be sent immediately
+func getQuietHoursAction(settings messages.TenantSettings, severity string, now time.Time) (action string, end time.Time, err error) {
+	if settings.QuietHours == nil {
+		return "", end, nil
+	}
+	action = settings.QuietHours.Routing[severity]
+	if action == "" {
+		return "", end, nil
+	}
+	quiet, end, err := isQuietTime(*settings.QuietHours, now)
+	if err != nil || !quiet {
+		return "", end, err
+	}
+	return action, end, nil
+}
+
+// isQuietTime checks if now is within the quiet hours and returns the end of these quiet hours
+func isQuietTime(quietHours messages.QuietHours, now time.Time) (quiet bool, end time.Time, err error) {
+	location, err := time.LoadLocation(quietHours.TimeZone)
+	if err != nil {
+		return false, end, err
+	}
+	start, err := time.Parse(quietHoursTimeFormat, quietHours.Start)
+	if err != nil {
+		return false, end, err
+	}
+	endTime, err := time.Parse(quietHoursTimeFormat, quietHours.End)
+	if err != nil {
+		return false, end, err
+	}
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := endTime.Hour()*60 + endTime.Minute()
+	if startMinute <= endMinute {
+		quiet = minute >= startMinute && minute < endMinute
+	} else {
+		//quiet hours span midnight
+		quiet = minute >= startMinute || minute < endMinute
+	}
+	if !quiet {
+		return false, end, nil
+	}
+	end = time.Date(local.Year(), local.Month(), local.Day(), endTime.Hour(), endTime.Minute(), 0, 0, location)
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return true, end, nil
+}
+
+func validateSeveritySettings(settings messages.TenantSettings) error {
+	for _, rule := range settings.SeverityRules {
+		if !slices.Contains(severities, rule.Severity) {
+			return errors.New("unknown severity " + rule.Severity)
+		}
+		if rule.ErrorMessagePattern != "" {
+			_, err := regexp.Compile(rule.ErrorMessagePattern)
+			if err != nil {
+				return errors.New("invalid error_message_pattern: " + err.Error())
+			}
+		}
+	}
+	if settings.QuietHours != nil {
+		_, err := time.LoadLocation(settings.QuietHours.TimeZone)
+		if err != nil {
+			return errors.New("invalid time_zone: " + err.Error())
+		}
+		_, err = time.Parse(quietHoursTimeFormat, settings.QuietHours.Start)
+		if err != nil {
+			return errors.New("invalid quiet hours start, expect format like 22:00")
+		}
+		_, err = time.Parse(quietHoursTimeFormat, settings.QuietHours.End)
+		if err != nil {
+			return errors.New("invalid quiet hours end, expect format like 07:00")
+		}
+		for severity, action := range settings.QuietHours.Routing {
+			if !slices.Contains(severities, severity) {
+				return errors.New("unknown severity " + severity)
+			}
+			if action != messages.QuietHoursActionSuppress && action != messages.QuietHoursActionDelay {
+				return errors.New("unknown quiet hours action " + action)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"github.com/SENERGY-Platform/process-incident-api/lib/notification"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+)
+
+func (this *Controller) SetTenantSettings(token string, settings messages.TenantSettings) (err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return err, http.StatusUnauthorized
+	}
+	err, code = checkTenantSettingsAccess(jwtToken, settings.TenantId)
+	if err != nil {
+		return err, code
+	}
+	err = this.templates.Validate(settings)
+	if err != nil {
+		return err, http.StatusBadRequest
+	}
+	err = validateNotificationSettings(settings)
+	if err != nil {
+		return err, http.StatusBadRequest
+	}
+	err = validateSeveritySettings(settings)
+	if err != nil {
+		return err, http.StatusBadRequest
+	}
+	err = this.db.SetTenantSettings(settings)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+func (this *Controller) GetTenantSettings(token string, tenantId string) (settings messages.TenantSettings, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return settings, err, http.StatusUnauthorized
+	}
+	err, code = checkTenantSettingsAccess(jwtToken, tenantId)
+	if err != nil {
+		return settings, err, code
+	}
+	settings, exists, err := this.db.GetTenantSettings(tenantId)
+	if err != nil {
+		return settings, err, http.StatusInternalServerError
+	}
+	if !exists {
+		return settings, errors.New("not found"), http.StatusNotFound
+	}
+	return settings, nil, http.StatusOK
+}
+
+func (this *Controller) DeleteTenantSettings(token string, tenantId string) (err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return err, http.StatusUnauthorized
+	}
+	err, code = checkTenantSettingsAccess(jwtToken, tenantId)
+	if err != nil {
+		return err, code
+	}
+	err = this.db.DeleteTenantSettings(tenantId)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+// admins may access the settings of every tenant, other users only their own
+func checkTenantSettingsAccess(jwtToken jwt.Token, tenantId string) (err error, code int) {
+	if tenantId == "" {
+		return errors.New("missing tenant id"), http.StatusBadRequest
+	}
+	if !jwtToken.IsAdmin() && jwtToken.GetUserId() != tenantId {
+		return errors.New("access to tenant settings denied"), http.StatusForbidden
+	}
+	return nil, http.StatusOK
+}
+
+// getTenantSettings returns empty settings if the tenant has none or they can not be loaded, so that default templates are used
+func (this *Controller) getTenantSettings(tenantId string) messages.TenantSettings {
+	settings, exists, err := this.db.GetTenantSettings(tenantId)
+	if err != nil {
+		log.Println("WARNING: unable to load tenant settings, use defaults", tenantId, err)
+		return messages.TenantSettings{TenantId: tenantId}
+	}
+	if !exists {
+		return messages.TenantSettings{TenantId: tenantId}
+	}
+	return settings
+}
+
+// notifyTenant renders the named template for the tenant of the incident and sends it to the tenant and its notification channels.
+// like incident notifications, it follows the quiet hours by the severity of the incident and counts against the rate limit of the tenant;
+// a rate limited message is dropped, because only incidents can be added to the digest
+func (this *Controller) notifyTenant(name string, data notification.TemplateData) {
+	if data.Incident.TenantId == "" {
+		return
+	}
+	settings := this.getTenantSettings(data.Incident.TenantId)
+	at, suppressed := this.getNotificationTime(data.Incident, settings, time.Now())
+	if suppressed {
+		return
+	}
+	limited, err := this.useNotificationRateLimit(data.Incident.TenantId, settings)
+	if err != nil {
+		log.Println("WARNING: unable to check notification rate limit, notify immediately", data.Incident.TenantId, err)
+	}
+	if limited {
+		this.logger.Info("notification dropped by rate limit", "snrgy-log-type", "process-incident", "user", data.Incident.TenantId, "process-definition-id", data.Incident.ProcessDefinitionId, "process-instance-id", data.Incident.ProcessInstanceId, "template", name)
+		return
+	}
+	title, message := this.templates.Render(name, settings, data)
+	msg := notification.Message{
+		UserId:  data.Incident.TenantId,
+		Title:   title,
+		Message: message,
+		Topic:   notification.Topic,
+	}
+	this.notifyAt(msg, at)
+	this.notifyChannels(data.Incident, msg, at)
+}
@@ -27,8 +27,8 @@ import (
 	developerNotifications "github.com/SENERGY-Platform/developer-notifications/pkg/client"
 	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
 	"github.com/SENERGY-Platform/process-incident-api/lib/notification"
-	"github.com/SENERGY-Platform/service-commons/pkg/cache"
 	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"github.com/google/uuid"
 )
 
 func (this *Controller) CreateIncident(token string, incident messages.Incident) (err error, code int) {
@@ -43,19 +43,48 @@ func (this *Controller) CreateIncident(token string, incident messages.Incident)
 	if err != nil {
 		return err, http.StatusBadRequest
 	}
+	return this.handleIncident(incident)
+}
+
+// handleIncident deduplicates, stores and handles the valid incident by its on-incident handler
+func (this *Controller) handleIncident(incident messages.Incident) (err error, code int) {
+	handling, registeredHandling, err := this.db.GetOnIncident(incident.ProcessDefinitionId)
+	if err != nil {
+		log.Println("ERROR: ", err)
+		debug.PrintStack()
+		return err, http.StatusInternalServerError
+	}
 	topic := incident.ProcessDefinitionId + "+" + incident.ProcessInstanceId
 	this.mux.Lock(topic)
 	defer this.mux.Unlock(topic)
-	//for every process instance an incident may only be handled once every 5 min
-	//use the cache.Use method to do incident handling, only if the process instance is not found in cache
+	window := this.getDeduplicationWindow(handling, registeredHandling)
+	if window <= 0 {
+		err = this.createIncident(incident, handling, registeredHandling)
+		if err != nil {
+			return err, http.StatusInternalServerError
+		}
+		return nil, http.StatusOK
+	}
+	//for every process instance an incident may only be handled once in the deduplication window
 	//incident.ProcessInstanceId should be enough as key but existing tests would fail, so the incident.ProcessDefinitionId is added
-	_, err = cache.Use[string](this.handledIncidentsCache, topic, func() (string, error) {
-		return "", this.createIncident(incident)
-	}, cache.NoValidation, 5*time.Minute)
+	handled, handledIncidentId, err := this.deduplicate(topic, incident.Id, window, func() error {
+		return this.createIncident(incident, handling, registeredHandling)
+	})
 	if err != nil {
 		return err, http.StatusInternalServerError
 	}
-	return err, http.StatusOK
+	//repeated reports of the same incident (e.g. by polling camunda) are no new occurrences
+	if !handled && handledIncidentId != "" && handledIncidentId != incident.Id {
+		lastSeen := incident.Time
+		if lastSeen.IsZero() {
+			lastSeen = time.Now()
+		}
+		err = this.db.AddIncidentOccurrence(handledIncidentId, lastSeen)
+		if err != nil {
+			return err, http.StatusInternalServerError
+		}
+	}
+	return nil, http.StatusOK
 }
 
 func (this *Controller) ValidateIncident(incident messages.Incident) error {
@@ -74,13 +103,18 @@ func (this *Controller) ValidateIncident(incident messages.Incident) error {
 	return nil
 }
 
-func (this *Controller) createIncident(incident messages.Incident) (err error) {
+func (this *Controller) createIncident(incident messages.Incident, handling messages.OnIncident, registeredHandling bool) (err error) {
 	this.metrics.NotifyIncidentMessage()
-	handling, registeredHandling, err := this.db.GetOnIncident(incident.ProcessDefinitionId)
-	if err != nil {
-		log.Println("ERROR: ", err)
-		debug.PrintStack()
-		return err
+	keepInstance := registeredHandling && (handling.KeepAlive || (handling.Restart && getRestartMode(handling) != messages.OnIncidentRestartModeProcess))
+	if keepInstance {
+		//incidents of kept process-instances stay open in camunda and may be reported repeatedly
+		_, exists, err := this.db.GetIncidents(incident.Id, incident.TenantId)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
 	}
 	name, err := this.camunda.GetProcessName(incident.ProcessDefinitionId, incident.TenantId)
 	if err != nil {
@@ -99,45 +133,59 @@ func (this *Controller) createIncident(incident messages.Incident) (err error) {
 		incident.BusinessKey = instance.BusinessKey
 	}
 
-	this.logger.Info("process-incident", "snrgy-log-type", "process-incident", "error", incident.ErrorMessage, "user", incident.TenantId, "deployment-name", incident.DeploymentName, "process-definition-id", incident.ProcessDefinitionId, "process-instance-id", incident.ProcessInstanceId)
+	settings := messages.TenantSettings{}
+	if incident.TenantId != "" {
+		settings = this.getTenantSettings(incident.TenantId)
+	}
+	//the severity is needed to route restart notifications
+	incident.Severity = this.getSeverity(settings, incident)
+
+	restart := registeredHandling && handling.Restart
+	restartDelay := time.Duration(0)
+	if restart {
+		restart, restartDelay, err = this.useRestartBudget(handling, incident)
+		if err != nil {
+			this.logger.Error("unable to check restart budget, skip restart", "snrgy-log-type", "process-incident", "error", err.Error(), "user", incident.TenantId, "process-definition-id", incident.ProcessDefinitionId, "process-instance-id", incident.ProcessInstanceId)
+			restart = false
+		}
+	}
+
+	this.logger.Info("process-incident", "snrgy-log-type", "process-incident", "error", incident.ErrorMessage, "user", incident.TenantId, "deployment-name", incident.DeploymentName, "process-definition-id", incident.ProcessDefinitionId, "process-instance-id", incident.ProcessInstanceId, "severity", incident.Severity)
 	if incident.TenantId != "" {
 		if !registeredHandling || handling.Notify {
-			msg := notification.Message{
-				UserId:  incident.TenantId,
-				Title:   "Process-Incident in " + incident.DeploymentName,
-				Message: incident.ErrorMessage,
-				Topic:   notification.Topic,
-			}
-			if registeredHandling && handling.Restart {
-				msg.Message = msg.Message + "\n\nprocess will be restarted"
-			}
-			this.Notify(msg)
+			this.notifyIncident(incident, settings, handling, registeredHandling && handling.Digest, restart)
 		}
 	}
-	err = this.db.SaveIncident(incident)
-	if err != nil {
-		return err
+	//the job and its stacktrace are removed with the process-instance
+	if incident.IncidentType == messages.CamundaIncidentTypeFailedJob && incident.Configuration != "" && incident.StackTrace == "" && this.config.IncidentStackTraceMaxLength >= 0 {
+		incident.StackTrace, err = this.camunda.GetJobStacktrace(incident.Configuration, this.config.IncidentStackTraceMaxLength, incident.TenantId)
+		if err != nil {
+			log.Println("WARNING: unable to get job stacktrace in createIncident(): ", err)
+		}
 	}
-	err = this.camunda.StopProcessInstance(incident.ProcessInstanceId, incident.TenantId)
+	err = this.db.SaveIncident(incident)
 	if err != nil {
 		return err
 	}
-	if registeredHandling && handling.Restart {
-		if incident.BusinessKey != "" {
-			err = this.camunda.StartProcessWithBusinessKey(incident.ProcessDefinitionId, incident.BusinessKey, incident.TenantId)
-		} else {
-			err = this.camunda.StartProcess(incident.ProcessDefinitionId, incident.TenantId)
+	var startVariables map[string]messages.CamundaVariable
+	if restart && restartDelay <= 0 && !keepInstance && handling.RestartWithStartParameters {
+		startVariables, err = this.getStartVariables(incident)
+		if err != nil {
+			this.notifyRestartFailed(incident, messages.OnIncidentRestartModeProcess, "unable to load start-parameters: "+err.Error())
+			restart = false
 		}
+	}
+	if !keepInstance {
+		err = this.camunda.StopProcessInstance(incident.ProcessInstanceId, incident.TenantId)
 		if err != nil {
-			this.logger.Error("unable to restart process", "snrgy-log-type", "process-incident", "error", err.Error(), "user", incident.TenantId, "deployment-name", incident.DeploymentName, "process-definition-id", incident.ProcessDefinitionId, "process-instance-id", incident.ProcessInstanceId)
-			if incident.TenantId != "" {
-				this.Notify(notification.Message{
-					UserId:  incident.TenantId,
-					Title:   "ERROR: unable to restart process after incident in: " + incident.DeploymentName,
-					Message: fmt.Sprintf("Restart-Error: %v \n\n Incident: %v \n", err, incident.ErrorMessage),
-					Topic:   notification.Topic,
-				})
-			}
+			return err
+		}
+	}
+	if restart {
+		if restartDelay > 0 {
+			this.scheduleRestart(handling, incident, time.Now().Add(restartDelay))
+		} else {
+			this.restart(handling, incident, startVariables)
 		}
 	}
 	return nil
@@ -178,12 +226,30 @@ func (this *Controller) SetOnIncidentHandler(token string, handler messages.OnIn
 	if err != nil {
 		return err, http.StatusUnauthorized
 	}
+	err = ValidateOnIncident(handler)
+	if err != nil {
+		return err, http.StatusBadRequest
+	}
+	err, code = this.checkOnIncidentHandlerAccess(jwtToken, handler.ProcessDefinitionId)
+	if err != nil {
+		return err, code
+	}
 	if !jwtToken.IsAdmin() {
-		return errors.New("only admins may create incidents"), http.StatusForbidden
+		handler.TenantId = jwtToken.GetUserId()
 	}
-	if handler.ProcessDefinitionId == "" {
-		return errors.New("missing process_definition_id"), http.StatusBadRequest
+	if handler.TenantId == "" {
+		//admins may omit the tenant_id to keep the owner of an existing handler
+		existing, exists, err := this.db.GetOnIncident(handler.ProcessDefinitionId)
+		if err != nil {
+			return err, http.StatusInternalServerError
+		}
+		if exists {
+			handler.TenantId = existing.TenantId
+		}
 	}
+	//setting a handler resets the restart budget
+	handler.RestartCount = 0
+	handler.RestartWindowStart = time.Time{}
 	err = this.db.SaveOnIncident(handler)
 	if err != nil {
 		return err, http.StatusInternalServerError
@@ -191,8 +257,97 @@ func (this *Controller) SetOnIncidentHandler(token string, handler messages.OnIn
 	return nil, http.StatusOK
 }
 
+func (this *Controller) GetOnIncidentHandler(token string, processDefinitionId string) (handler messages.OnIncident, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return handler, err, http.StatusUnauthorized
+	}
+	err, code = this.checkOnIncidentHandlerAccess(jwtToken, processDefinitionId)
+	if err != nil {
+		return handler, err, code
+	}
+	handler, exists, err := this.db.GetOnIncident(processDefinitionId)
+	if err != nil {
+		return handler, err, http.StatusInternalServerError
+	}
+	if !exists {
+		return handler, errors.New("not found"), http.StatusNotFound
+	}
+	return handler, nil, http.StatusOK
+}
+
+// ListOnIncidentHandlers lists all handlers for admins and the handlers owned by the requesting user otherwise
+func (this *Controller) ListOnIncidentHandlers(token string, limit int, offset int) (handlers []messages.OnIncident, err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return handlers, err, http.StatusUnauthorized
+	}
+	tenantId := ""
+	if !jwtToken.IsAdmin() {
+		tenantId = jwtToken.GetUserId()
+	}
+	handlers, err = this.db.ListOnIncidents(tenantId, limit, offset)
+	if err != nil {
+		return handlers, err, http.StatusInternalServerError
+	}
+	return handlers, nil, http.StatusOK
+}
+
+func (this *Controller) DeleteOnIncidentHandler(token string, processDefinitionId string) (err error, code int) {
+	jwtToken, err := jwt.Parse(token)
+	if err != nil {
+		return err, http.StatusUnauthorized
+	}
+	err, code = this.checkOnIncidentHandlerAccess(jwtToken, processDefinitionId)
+	if err != nil {
+		return err, code
+	}
+	err = this.db.DeleteOnIncidentByDefinitionId(processDefinitionId)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	return nil, http.StatusOK
+}
+
+// admins may access every handler, other users only handlers of process-definitions they own in their camunda shard
+func (this *Controller) checkOnIncidentHandlerAccess(jwtToken jwt.Token, processDefinitionId string) (err error, code int) {
+	if jwtToken.IsAdmin() {
+		return nil, http.StatusOK
+	}
+	allowed, err := this.camunda.CheckProcessDefinitionAccess(processDefinitionId, jwtToken.GetUserId())
+	if err != nil {
+		log.Println("ERROR: unable to check process-definition access", err)
+		return errors.New("unable to check process-definition access"), http.StatusInternalServerError
+	}
+	if !allowed {
+		return errors.New("access to process-definition denied"), http.StatusForbidden
+	}
+	return nil, http.StatusOK
+}
+
+// Notify stores the notification in the outbox, from where it is delivered by the notification dispatcher
 func (this *Controller) Notify(msg notification.Message) {
-	_ = notification.Send(this.config.NotificationUrl, msg)
+	this.notifyAt(msg, time.Now())
+}
+
+// notifyAt stores the notification in the outbox, to be delivered not before at
+func (this *Controller) notifyAt(msg notification.Message, at time.Time) {
+	if this.config.NotificationUrl != "" {
+		err := this.db.EnqueueNotification(messages.OutboxEntry{
+			Id:          uuid.NewString(),
+			UserId:      msg.UserId,
+			Title:       msg.Title,
+			Message:     msg.Message,
+			Topic:       msg.Topic,
+			Status:      messages.OutboxStatusPending,
+			NextAttempt: at,
+			Created:     time.Now(),
+		})
+		if err != nil {
+			log.Println("ERROR: unable to store notification in outbox, try direct delivery", err)
+			_ = notification.Send(this.config.NotificationUrl, msg)
+		}
+	}
 	if this.devNotifications != nil {
 		go func() {
 			if this.config.Debug {
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BackfillJobDocument stores errors as list, because shard urls contain dots, which are not allowed in field names
+type BackfillJobDocument struct {
+	Id        string                       `bson:"id"`
+	Request   BackfillRequestDocument      `bson:"request"`
+	Status    string                       `bson:"status"`
+	Started   time.Time                    `bson:"started"`
+	Finished  time.Time                    `bson:"finished,omitempty"`
+	Updated   time.Time                    `bson:"updated"`
+	Read      int64                        `bson:"read"`
+	Inserted  int64                        `bson:"inserted"`
+	Skipped   int64                        `bson:"skipped"`
+	Invalid   int64                        `bson:"invalid"`
+	Errors    []BackfillShardErrorDocument `bson:"errors"`
+	ExpiresAt time.Time                    `bson:"expires_at"`
+}
+
+type BackfillRequestDocument struct {
+	Shards []string  `bson:"shards"`
+	From   time.Time `bson:"from"`
+	Until  time.Time `bson:"until"`
+}
+
+type BackfillShardErrorDocument struct {
+	Shard string `bson:"shard"`
+	Error string `bson:"error"`
+}
+
+var BackfillJobBson = getBsonFieldObject[BackfillJobDocument]()
+
+func (this *mongoclient) backfillsCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoBackfillCollectionName)
+}
+
+// SaveBackfillJob creates or replaces the job; the job is removed by mongodb after expiresAt
+func (this *mongoclient) SaveBackfillJob(job messages.BackfillJob, expiresAt time.Time) error {
+	doc := BackfillJobDocument{
+		Id:        job.Id,
+		Request:   BackfillRequestDocument(job.Request),
+		Status:    job.Status,
+		Started:   job.Started,
+		Finished:  job.Finished,
+		Updated:   job.Updated,
+		Read:      job.Read,
+		Inserted:  job.Inserted,
+		Skipped:   job.Skipped,
+		Invalid:   job.Invalid,
+		Errors:    []BackfillShardErrorDocument{},
+		ExpiresAt: expiresAt,
+	}
+	for shard, err := range job.Errors {
+		doc.Errors = append(doc.Errors, BackfillShardErrorDocument{Shard: shard, Error: err})
+	}
+	_, err := this.backfillsCollection().ReplaceOne(this.getTimeoutContext(), bson.M{BackfillJobBson.Id: job.Id}, doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (this *mongoclient) GetBackfillJob(id string) (job messages.BackfillJob, exists bool, err error) {
+	doc := BackfillJobDocument{}
+	err = this.backfillsCollection().FindOne(this.getTimeoutContext(), bson.M{BackfillJobBson.Id: id}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return job, false, nil
+	}
+	if err != nil {
+		return job, false, err
+	}
+	return doc.toBackfillJob(), true, nil
+}
+
+func (this *mongoclient) ListBackfillJobs() (jobs []messages.BackfillJob, err error) {
+	result, err := this.backfillsCollection().Find(this.getTimeoutContext(), bson.M{}, options.Find().SetSort(bson.D{{Key: "started", Value: 1}}))
+	if err != nil {
+		return jobs, err
+	}
+	jobs = []messages.BackfillJob{}
+	for result.Next(context.Background()) {
+		doc := BackfillJobDocument{}
+		err = result.Decode(&doc)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, doc.toBackfillJob())
+	}
+	err = result.Err()
+	return jobs, err
+}
+
+func (this BackfillJobDocument) toBackfillJob() messages.BackfillJob {
+	job := messages.BackfillJob{
+		Id:       this.Id,
+		Request:  messages.BackfillRequest(this.Request),
+		Status:   this.Status,
+		Started:  this.Started,
+		Finished: this.Finished,
+		Updated:  this.Updated,
+		Read:     this.Read,
+		Inserted: this.Inserted,
+		Skipped:  this.Skipped,
+		Invalid:  this.Invalid,
+		Errors:   map[string]string{},
+	}
+	for _, e := range this.Errors {
+		job.Errors[e.Shard] = e.Error
+	}
+	return job
+}
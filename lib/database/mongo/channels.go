@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var NotificationChannelBson = getBsonFieldObject[messages.NotificationChannel]()
+
+func (this *mongoclient) channelsCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoChannelCollectionName)
+}
+
+func (this *mongoclient) SaveNotificationChannel(channel messages.NotificationChannel) error {
+	_, err := this.channelsCollection().ReplaceOne(this.getTimeoutContext(), bson.M{NotificationChannelBson.Id: channel.Id}, channel, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (this *mongoclient) GetNotificationChannel(id string) (channel messages.NotificationChannel, exists bool, err error) {
+	err = this.channelsCollection().FindOne(this.getTimeoutContext(), bson.M{NotificationChannelBson.Id: id}).Decode(&channel)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return channel, false, nil
+	}
+	if err != nil {
+		return channel, false, err
+	}
+	return channel, true, nil
+}
+
+// ListNotificationChannels lists all channels if tenantId is empty
+func (this *mongoclient) ListNotificationChannels(tenantId string, limit int, offset int) (channels []messages.NotificationChannel, err error) {
+	filter := bson.M{}
+	if tenantId != "" {
+		filter[NotificationChannelBson.TenantId] = tenantId
+	}
+	option := options.Find().
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: NotificationChannelBson.Id, Value: 1}})
+	return this.findNotificationChannels(filter, option)
+}
+
+// FindIncidentNotificationChannels returns the channels of the tenant, that are not limited to another process-definition
+func (this *mongoclient) FindIncidentNotificationChannels(tenantId string, processDefinitionId string) (channels []messages.NotificationChannel, err error) {
+	filter := bson.M{
+		NotificationChannelBson.TenantId:            tenantId,
+		NotificationChannelBson.ProcessDefinitionId: bson.M{"$in": bson.A{processDefinitionId, "", nil}},
+	}
+	return this.findNotificationChannels(filter, options.Find().SetSort(bson.D{{Key: NotificationChannelBson.Id, Value: 1}}))
+}
+
+func (this *mongoclient) findNotificationChannels(filter bson.M, option *options.FindOptions) (channels []messages.NotificationChannel, err error) {
+	result, err := this.channelsCollection().Find(this.getTimeoutContext(), filter, option)
+	if err != nil {
+		return channels, err
+	}
+	for result.Next(context.Background()) {
+		channel := messages.NotificationChannel{}
+		err = result.Decode(&channel)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+	err = result.Err()
+	return channels, err
+}
+
+func (this *mongoclient) DeleteNotificationChannel(id string) error {
+	_, err := this.channelsCollection().DeleteOne(this.getTimeoutContext(), bson.M{NotificationChannelBson.Id: id})
+	return err
+}
+
+func (this *mongoclient) DeleteNotificationChannelsByDefinitionId(definitionId string) error {
+	_, err := this.channelsCollection().DeleteMany(this.getTimeoutContext(), bson.M{NotificationChannelBson.ProcessDefinitionId: definitionId})
+	return err
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var CommentBson = getBsonFieldObject[messages.IncidentComment]()
+
+func (this *mongoclient) commentsCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoCommentCollectionName)
+}
+
+func (this *mongoclient) SaveIncidentComment(comment messages.IncidentComment) error {
+	_, err := this.commentsCollection().ReplaceOne(this.getTimeoutContext(), bson.M{CommentBson.Id: comment.Id}, comment, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (this *mongoclient) ListIncidentComments(incidentId string, user string) (comments []messages.IncidentComment, err error) {
+	option := options.Find().SetSort(bson.D{{Key: "time", Value: 1}})
+	result, err := this.commentsCollection().Find(this.getTimeoutContext(), bson.M{CommentBson.IncidentId: incidentId, CommentBson.TenantId: user}, option)
+	if err != nil {
+		return comments, err
+	}
+	for result.Next(context.Background()) {
+		comment := messages.IncidentComment{}
+		err = result.Decode(&comment)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	err = result.Err()
+	return comments, err
+}
+
+func (this *mongoclient) DeleteCommentsByInstanceId(id string) error {
+	_, err := this.commentsCollection().DeleteMany(this.getTimeoutContext(), bson.M{CommentBson.ProcessInstanceId: id})
+	return err
+}
+
+func (this *mongoclient) DeleteCommentsByDefinitionId(id string) error {
+	_, err := this.commentsCollection().DeleteMany(this.getTimeoutContext(), bson.M{CommentBson.ProcessDefinitionId: id})
+	return err
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var NotificationDigestBson = getBsonFieldObject[messages.NotificationDigest]()
+
+func (this *mongoclient) digestsCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoDigestCollectionName)
+}
+
+// AddToNotificationDigest adds the incident to the pending digest of the tenant; a new digest is created with due and interval.
+// an existing digest is postponed to notBefore, if it is set and after the current due time
+func (this *mongoclient) AddToNotificationDigest(tenantId string, incident messages.DigestIncident, due time.Time, notBefore time.Time, interval string) error {
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"since":    incident.Time,
+			"due":      due,
+			"interval": interval,
+		},
+		"$inc":      bson.M{"count": 1},
+		"$addToSet": bson.M{"processes": incident.DeploymentName},
+		"$push": bson.M{"incidents": bson.M{
+			"$each":  bson.A{incident},
+			"$slice": -messages.DigestMaxIncidents,
+		}},
+	}
+	_, err := this.digestsCollection().UpdateOne(this.getTimeoutContext(), bson.M{NotificationDigestBson.TenantId: tenantId}, update, options.Update().SetUpsert(true))
+	if err != nil || notBefore.IsZero() {
+		return err
+	}
+	_, err = this.digestsCollection().UpdateOne(this.getTimeoutContext(), bson.M{
+		NotificationDigestBson.TenantId: tenantId,
+		"due":                           bson.M{"$lt": notBefore},
+	}, bson.M{"$set": bson.M{"due": notBefore}})
+	return err
+}
+
+// TakeDueNotificationDigest removes and returns a digest that is due; incidents added afterwards start a new digest
+func (this *mongoclient) TakeDueNotificationDigest(now time.Time) (digest messages.NotificationDigest, found bool, err error) {
+	err = this.digestsCollection().FindOneAndDelete(this.getTimeoutContext(), bson.M{"due": bson.M{"$lte": now}}).Decode(&digest)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return digest, false, nil
+	}
+	if err != nil {
+		return digest, false, err
+	}
+	return digest, true, nil
+}
+
+type NotificationRateCount struct {
+	TenantId    string    `bson:"tenant_id"`
+	WindowStart time.Time `bson:"window_start"`
+	Count       int64     `bson:"count"`
+}
+
+var NotificationRateCountBson = getBsonFieldObject[NotificationRateCount]()
+
+func (this *mongoclient) rateLimitsCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoRateLimitCollectionName)
+}
+
+// IncrementNotificationRateCount atomically counts a notification of the tenant and returns the count within the current window;
+// the count is reset if the current window is older than window
+func (this *mongoclient) IncrementNotificationRateCount(tenantId string, now time.Time, window time.Duration) (count int64, err error) {
+	newWindow := bson.M{"$or": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$window_start", nil}}, nil}},
+		bson.M{"$lt": bson.A{"$window_start", now.Add(-window)}},
+	}}
+	update := bson.A{bson.M{"$set": bson.M{
+		"window_start": bson.M{"$cond": bson.A{newWindow, now, "$window_start"}},
+		"count":        bson.M{"$cond": bson.A{newWindow, 1, bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$count", 0}}, 1}}}},
+	}}}
+	result := NotificationRateCount{}
+	err = this.rateLimitsCollection().FindOneAndUpdate(this.getTimeoutContext(), bson.M{NotificationRateCountBson.TenantId: tenantId}, update, options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&result)
+	return result.Count, err
+}
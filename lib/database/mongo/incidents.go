@@ -24,6 +24,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"log"
+	"time"
 )
 
 func (this *mongoclient) collection() *mongo.Collection {
@@ -32,10 +33,10 @@ func (this *mongoclient) collection() *mongo.Collection {
 
 func (this *mongoclient) GetIncidents(id string, user string) (incident messages.IncidentMessage, exists bool, err error) {
 	result := this.collection().FindOne(this.getTimeoutContext(), bson.M{"id": id, "tenant_id": user})
+	err = result.Err()
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return incident, false, nil
 	}
-	err = result.Err()
 	if err != nil {
 		return incident, exists, err
 	}
@@ -46,66 +47,191 @@ func (this *mongoclient) GetIncidents(id string, user string) (incident messages
 	return incident, true, err
 }
 
-func (this *mongoclient) FindIncidents(externalTaskId string, processDefinitionId string, processInstanceId string, limit int, offset int, sortby string, asc bool, user string) (incidents []messages.IncidentMessage, err error) {
+func (this *mongoclient) FindIncidents(incidentFilter messages.IncidentFilter, cursor *messages.IncidentCursor, limit int, offset int, sortby string, asc bool, user string) (incidents []messages.IncidentMessage, err error) {
 	if this.config.Debug {
-		log.Println("DEBUG: FindIncidents()", externalTaskId, processDefinitionId, processInstanceId)
-	}
-	filter := bson.M{"tenant_id": user}
-	if processDefinitionId != "" {
-		filter["process_definition_id"] = processDefinitionId
-	}
-	if processInstanceId != "" {
-		filter["process_instance_id"] = processInstanceId
-	}
-	if externalTaskId != "" {
-		filter["external_task_id"] = externalTaskId
-	}
-	if this.config.Debug {
-		log.Println("DEBUG: FindIncidents() filter = ", filter)
+		log.Printf("DEBUG: FindIncidents() %#v\n", incidentFilter)
 	}
+	filter := getIncidentsFilter(incidentFilter, user)
 
 	direction := int32(1)
+	compare := "$gt"
 	if !asc {
 		direction = int32(-1)
+		compare = "$lt"
 	}
 
+	//the id is used as tiebreaker to get a stable order for cursors
+	sort := bson.D{{sortby, direction}}
+	if sortby != "id" {
+		sort = append(sort, bson.E{Key: "id", Value: direction})
+	}
+
+	//stack traces may be large and are only returned for single incidents
 	option := options.Find().
-		SetSkip(int64(offset)).
 		SetLimit(int64(limit)).
-		SetSort(bson.D{{sortby, direction}})
+		SetSort(sort).
+		SetProjection(bson.M{"stack_trace": 0})
+
+	if cursor != nil {
+		value, err := cursor.TypedValue()
+		if err != nil {
+			return incidents, err
+		}
+		if sortby == "id" {
+			filter["id"] = bson.M{compare: cursor.Id}
+		} else {
+			filter["$or"] = []bson.M{
+				{sortby: bson.M{compare: value}},
+				{sortby: value, "id": bson.M{compare: cursor.Id}},
+			}
+		}
+	} else {
+		option.SetSkip(int64(offset))
+	}
+
+	if this.config.Debug {
+		log.Println("DEBUG: FindIncidents() filter = ", filter)
+	}
 
-	cursor, err := this.collection().Find(this.getTimeoutContext(), filter, option)
+	result, err := this.collection().Find(this.getTimeoutContext(), filter, option)
 	if err != nil {
 		return incidents, err
 	}
-	for cursor.Next(context.Background()) {
+	for result.Next(context.Background()) {
 		incident := messages.IncidentMessage{}
-		err = cursor.Decode(&incident)
+		err = result.Decode(&incident)
 		if err != nil {
 			return nil, err
 		}
 		incidents = append(incidents, incident)
 	}
-	err = cursor.Err()
+	err = result.Err()
 	return incidents, err
 }
 
+func (this *mongoclient) CountIncidents(incidentFilter messages.IncidentFilter, user string) (count int64, err error) {
+	filter := getIncidentsFilter(incidentFilter, user)
+	if this.config.Debug {
+		log.Println("DEBUG: CountIncidents() filter = ", filter)
+	}
+	return this.collection().CountDocuments(this.getTimeoutContext(), filter)
+}
+
+func getIncidentsFilter(incidentFilter messages.IncidentFilter, user string) bson.M {
+	filter := bson.M{"tenant_id": user}
+	if incidentFilter.ProcessDefinitionId != "" {
+		filter["process_definition_id"] = incidentFilter.ProcessDefinitionId
+	}
+	if incidentFilter.ProcessInstanceId != "" {
+		filter["process_instance_id"] = incidentFilter.ProcessInstanceId
+	}
+	if incidentFilter.ExternalTaskId != "" {
+		filter["external_task_id"] = incidentFilter.ExternalTaskId
+	}
+	timeFilter := bson.M{}
+	if !incidentFilter.From.IsZero() {
+		timeFilter["$gte"] = incidentFilter.From
+	}
+	if !incidentFilter.Until.IsZero() {
+		timeFilter["$lte"] = incidentFilter.Until
+	}
+	if len(timeFilter) > 0 {
+		filter["time"] = timeFilter
+	}
+	if incidentFilter.Search != "" {
+		filter["$text"] = bson.M{"$search": incidentFilter.Search}
+	}
+	if incidentFilter.Status == messages.IncidentStatusOpen {
+		//incidents stored without status are open
+		filter["status"] = bson.M{"$in": []interface{}{messages.IncidentStatusOpen, "", nil}}
+	} else if incidentFilter.Status != "" {
+		filter["status"] = incidentFilter.Status
+	}
+	return filter
+}
+
+// incidentLifecycleFields are only set when an incident is created, so that repeated reports keep the status and occurrence count of the stored incident
+var incidentLifecycleFields = []string{"status", "status_changed_by", "status_changed_at", "occurrence_count", "last_seen"}
+
+// SaveIncident creates the incident or updates the fields reported by camunda; empty optional fields (e.g. stack_trace) keep their stored value
 func (this *mongoclient) SaveIncident(incident messages.Incident) error {
-	_, err := this.collection().ReplaceOne(this.getTimeoutContext(), bson.M{"id": incident.Id}, incident, options.Replace().SetUpsert(true))
+	raw, err := bson.Marshal(incident)
+	if err != nil {
+		return err
+	}
+	set := bson.M{}
+	err = bson.Unmarshal(raw, &set)
+	if err != nil {
+		return err
+	}
+	setOnInsert := bson.M{}
+	for _, field := range incidentLifecycleFields {
+		if value, ok := set[field]; ok {
+			setOnInsert[field] = value
+			delete(set, field)
+		}
+	}
+	update := bson.M{"$set": set}
+	if len(setOnInsert) > 0 {
+		update["$setOnInsert"] = setOnInsert
+	}
+	_, err = this.collection().UpdateOne(this.getTimeoutContext(), bson.M{"id": incident.Id}, update, options.Update().SetUpsert(true))
 	return err
 }
 
+// InsertIncidentIfMissing stores the incident only if no incident with the same id exists
+func (this *mongoclient) InsertIncidentIfMissing(incident messages.Incident) (inserted bool, err error) {
+	result, err := this.collection().UpdateOne(this.getTimeoutContext(), bson.M{"id": incident.Id}, bson.M{"$setOnInsert": incident}, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// AddIncidentOccurrence increments the occurrence count of the incident; incidents without count are counted as one occurrence
+func (this *mongoclient) AddIncidentOccurrence(id string, lastSeen time.Time) error {
+	update := bson.A{bson.M{"$set": bson.M{
+		"occurrence_count": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$occurrence_count", 1}}, 1}},
+		"last_seen":        lastSeen,
+	}}}
+	_, err := this.collection().UpdateOne(this.getTimeoutContext(), bson.M{"id": id}, update)
+	return err
+}
+
+func (this *mongoclient) SetIncidentStatus(id string, user string, status string, changedBy string, changedAt time.Time) (exists bool, err error) {
+	result, err := this.collection().UpdateOne(this.getTimeoutContext(), bson.M{"id": id, "tenant_id": user}, bson.M{"$set": bson.M{
+		"status":            status,
+		"status_changed_by": changedBy,
+		"status_changed_at": changedAt,
+	}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
 func (this *mongoclient) DeleteByDefinitionId(id string) error {
 	err := this.DeleteIncidentByDefinitionId(id)
 	if err != nil {
 		return err
 	}
+	err = this.DeleteCommentsByDefinitionId(id)
+	if err != nil {
+		return err
+	}
+	err = this.DeleteNotificationChannelsByDefinitionId(id)
+	if err != nil {
+		return err
+	}
 	return this.DeleteOnIncidentByDefinitionId(id)
 }
 
 func (this *mongoclient) DeleteIncidentByInstanceId(id string) error {
 	_, err := this.collection().DeleteMany(this.getTimeoutContext(), bson.M{"process_instance_id": id})
-	return err
+	if err != nil {
+		return err
+	}
+	return this.DeleteCommentsByInstanceId(id)
 }
 
 func (this *mongoclient) DeleteIncidentByDefinitionId(id string) error {
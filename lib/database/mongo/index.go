@@ -51,10 +51,112 @@ func (this *mongoclient) init() error {
 	if err != nil {
 		return err
 	}
+	err = this.ensureIndex(this.collection(), "status_index", "status", true, false)
+	if err != nil {
+		return err
+	}
+	err = this.ensureTextIndex(this.collection(), "search_index", "error_message", "deployment_name", "business_key")
+	if err != nil {
+		return err
+	}
 	err = this.ensureIndex(this.onIncidentsCollection(), "on_incident_process_definition_id_index", OnIncidentBson.ProcessDefinitionId, true, false)
 	if err != nil {
 		return err
 	}
+	err = this.ensureIndex(this.onIncidentsCollection(), "on_incident_tenant_id_index", OnIncidentBson.TenantId, true, false)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.commentsCollection(), "comment_id_index", CommentBson.Id, true, true)
+	if err != nil {
+		return err
+	}
+	err = this.ensureCompoundIndex(this.commentsCollection(), "comment_incident_tenant_index", true, false, CommentBson.IncidentId, CommentBson.TenantId)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.commentsCollection(), "comment_process_instance_id_index", CommentBson.ProcessInstanceId, true, false)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.commentsCollection(), "comment_process_definition_id_index", CommentBson.ProcessDefinitionId, true, false)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.watermarksCollection(), "watermark_shard_index", ShardWatermarkBson.Shard, true, true)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.leasesCollection(), "lease_name_index", LeaseBson.Name, true, true)
+	if err != nil {
+		return err
+	}
+	//expired leases may be replaced at any time and are removed by mongodb to keep the collection small
+	err = this.ensureTTLIndex(this.leasesCollection(), "lease_expires_at_index", "expires_at", 0)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.outboxCollection(), "outbox_id_index", OutboxEntryBson.Id, true, true)
+	if err != nil {
+		return err
+	}
+	err = this.ensureCompoundIndex(this.outboxCollection(), "outbox_status_next_attempt_index", true, false, OutboxEntryBson.Status, "next_attempt")
+	if err != nil {
+		return err
+	}
+	//only dead-lettered entries have an expiration; delivered entries are removed by the dispatcher
+	err = this.ensureTTLIndex(this.outboxCollection(), "outbox_expires_at_index", "expires_at", 0)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.channelsCollection(), "channel_id_index", NotificationChannelBson.Id, true, true)
+	if err != nil {
+		return err
+	}
+	err = this.ensureCompoundIndex(this.channelsCollection(), "channel_tenant_process_definition_index", true, false, NotificationChannelBson.TenantId, NotificationChannelBson.ProcessDefinitionId)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.tenantSettingsCollection(), "tenant_settings_tenant_id_index", TenantSettingsBson.TenantId, true, true)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.digestsCollection(), "digest_tenant_id_index", NotificationDigestBson.TenantId, true, true)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.digestsCollection(), "digest_due_index", "due", true, false)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.rateLimitsCollection(), "rate_limit_tenant_id_index", NotificationRateCountBson.TenantId, true, true)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.pendingRestartsCollection(), "pending_restart_id_index", PendingRestartBson.Id, true, true)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.pendingRestartsCollection(), "pending_restart_due_index", "due", true, false)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.pollFailuresCollection(), "poll_failure_incident_id_index", CamundaIncidentFailureBson.IncidentId, true, true)
+	if err != nil {
+		return err
+	}
+	err = this.ensureTTLIndex(this.pollFailuresCollection(), "poll_failure_expires_at_index", "expires_at", 0)
+	if err != nil {
+		return err
+	}
+	err = this.ensureIndex(this.backfillsCollection(), "backfill_id_index", BackfillJobBson.Id, true, true)
+	if err != nil {
+		return err
+	}
+	err = this.ensureTTLIndex(this.backfillsCollection(), "backfill_expires_at_index", "expires_at", 0)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -157,6 +259,15 @@ func (this *mongoclient) ensureIndex(collection *mongo.Collection, indexname str
 	return err
 }
 
+func (this *mongoclient) ensureTTLIndex(collection *mongo.Collection, indexname string, indexKey string, expireAfterSeconds int32) error {
+	ctx := this.getTimeoutContext()
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{indexKey, int32(1)}},
+		Options: options.Index().SetName(indexname).SetExpireAfterSeconds(expireAfterSeconds),
+	})
+	return err
+}
+
 func (this *mongoclient) ensureTextIndex(collection *mongo.Collection, indexname string, indexKeys ...string) error {
 	if len(indexKeys) == 0 {
 		return errors.New("expect at least one key")
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var LeaseBson = getBsonFieldObject[messages.Lease]()
+
+func (this *mongoclient) leasesCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoLeaseCollectionName)
+}
+
+// TryLease acquires the lease if it does not exist, is expired or is already held by owner.
+// if the lease is held by someone else, acquired is false and current contains the existing lease
+func (this *mongoclient) TryLease(name string, owner string, value string, duration time.Duration) (acquired bool, current messages.Lease, err error) {
+	now := time.Now()
+	lease := messages.Lease{Name: name, Owner: owner, Value: value, ExpiresAt: now.Add(duration)}
+	filter := bson.M{LeaseBson.Name: name, "$or": bson.A{
+		bson.M{"expires_at": bson.M{"$lte": now}},
+		bson.M{LeaseBson.Owner: owner},
+	}}
+	_, err = this.leasesCollection().ReplaceOne(this.getTimeoutContext(), filter, lease, options.Replace().SetUpsert(true))
+	if err == nil {
+		return true, lease, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return false, current, err
+	}
+	err = this.leasesCollection().FindOne(this.getTimeoutContext(), bson.M{LeaseBson.Name: name}).Decode(&current)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		//released in the meantime
+		return false, current, nil
+	}
+	return false, current, err
+}
+
+func (this *mongoclient) ReleaseLease(name string, owner string) error {
+	_, err := this.leasesCollection().DeleteOne(this.getTimeoutContext(), bson.M{LeaseBson.Name: name, LeaseBson.Owner: owner})
+	return err
+}
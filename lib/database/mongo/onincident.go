@@ -17,11 +17,13 @@
 package mongo
 
 import (
+	"context"
 	"errors"
 	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 var OnIncidentBson = getBsonFieldObject[messages.OnIncident]()
@@ -54,3 +56,53 @@ func (this *mongoclient) GetOnIncident(definitionId string) (handler messages.On
 	}
 	return handler, true, err
 }
+
+// ListOnIncidents lists all handlers if tenantId is empty
+func (this *mongoclient) ListOnIncidents(tenantId string, limit int, offset int) (handlers []messages.OnIncident, err error) {
+	filter := bson.M{}
+	if tenantId != "" {
+		filter[OnIncidentBson.TenantId] = tenantId
+	}
+	option := options.Find().
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: OnIncidentBson.ProcessDefinitionId, Value: 1}})
+	result, err := this.onIncidentsCollection().Find(this.getTimeoutContext(), filter, option)
+	if err != nil {
+		return handlers, err
+	}
+	for result.Next(context.Background()) {
+		handler := messages.OnIncident{}
+		err = result.Decode(&handler)
+		if err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, handler)
+	}
+	err = result.Err()
+	return handlers, err
+}
+
+// IncrementOnIncidentRestartCount atomically increments the restart count of the handler;
+// the count is reset if the current restart window is older than window
+func (this *mongoclient) IncrementOnIncidentRestartCount(definitionId string, now time.Time, window time.Duration) (handler messages.OnIncident, exists bool, err error) {
+	newWindow := bson.M{"$or": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$restart_window_start", nil}}, nil}},
+		bson.M{"$lt": bson.A{"$restart_window_start", now.Add(-window)}},
+	}}
+	update := bson.A{bson.M{"$set": bson.M{
+		"restart_window_start": bson.M{"$cond": bson.A{newWindow, now, "$restart_window_start"}},
+		"restart_count":        bson.M{"$cond": bson.A{newWindow, 1, bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$restart_count", 0}}, 1}}}},
+	}}}
+	result := this.onIncidentsCollection().FindOneAndUpdate(this.getTimeoutContext(), bson.M{OnIncidentBson.ProcessDefinitionId: definitionId}, update, options.FindOneAndUpdate().SetReturnDocument(options.After))
+	err = result.Decode(&handler)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return handler, false, nil
+	}
+	return handler, err == nil, err
+}
+
+func (this *mongoclient) DisableOnIncidentRestart(definitionId string) error {
+	_, err := this.onIncidentsCollection().UpdateOne(this.getTimeoutContext(), bson.M{OnIncidentBson.ProcessDefinitionId: definitionId}, bson.M{"$set": bson.M{"restart": false}})
+	return err
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var OutboxEntryBson = getBsonFieldObject[messages.OutboxEntry]()
+
+func (this *mongoclient) outboxCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoOutboxCollectionName)
+}
+
+func (this *mongoclient) EnqueueNotification(entry messages.OutboxEntry) error {
+	_, err := this.outboxCollection().InsertOne(this.getTimeoutContext(), entry)
+	return err
+}
+
+// ClaimNotification returns the oldest pending entry that is due and postpones its next attempt to lockUntil,
+// so that other dispatchers do not deliver it at the same time
+func (this *mongoclient) ClaimNotification(now time.Time, lockUntil time.Time) (entry messages.OutboxEntry, found bool, err error) {
+	filter := bson.M{
+		OutboxEntryBson.Status: messages.OutboxStatusPending,
+		"next_attempt":         bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt": lockUntil}}
+	option := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt", Value: 1}}).
+		SetReturnDocument(options.After)
+	err = this.outboxCollection().FindOneAndUpdate(this.getTimeoutContext(), filter, update, option).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return entry, false, nil
+	}
+	if err != nil {
+		return entry, false, err
+	}
+	return entry, true, nil
+}
+
+func (this *mongoclient) UpdateNotification(entry messages.OutboxEntry) error {
+	_, err := this.outboxCollection().ReplaceOne(this.getTimeoutContext(), bson.M{OutboxEntryBson.Id: entry.Id}, entry)
+	return err
+}
+
+func (this *mongoclient) RemoveNotification(id string) error {
+	_, err := this.outboxCollection().DeleteOne(this.getTimeoutContext(), bson.M{OutboxEntryBson.Id: id})
+	return err
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var CamundaIncidentFailureBson = getBsonFieldObject[messages.CamundaIncidentFailure]()
+
+func (this *mongoclient) pollFailuresCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoPollFailureCollectionName)
+}
+
+// AddCamundaIncidentFailure increments the attempts of the incident and returns the new count; Attempts of the failure is ignored
+func (this *mongoclient) AddCamundaIncidentFailure(failure messages.CamundaIncidentFailure) (attempts int64, err error) {
+	update := bson.M{
+		"$inc": bson.M{"attempts": 1},
+		"$set": bson.M{
+			CamundaIncidentFailureBson.Shard:     failure.Shard,
+			"timestamp":                          failure.Timestamp,
+			CamundaIncidentFailureBson.LastError: failure.LastError,
+			"last_attempt":                       failure.LastAttempt,
+			"expires_at":                         failure.ExpiresAt,
+		},
+	}
+	option := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	result := messages.CamundaIncidentFailure{}
+	err = this.pollFailuresCollection().FindOneAndUpdate(this.getTimeoutContext(), bson.M{CamundaIncidentFailureBson.IncidentId: failure.IncidentId}, update, option).Decode(&result)
+	if err != nil {
+		return 0, err
+	}
+	return result.Attempts, nil
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var PendingRestartBson = getBsonFieldObject[messages.PendingRestart]()
+
+func (this *mongoclient) pendingRestartsCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoPendingRestartCollectionName)
+}
+
+func (this *mongoclient) SavePendingRestart(restart messages.PendingRestart) error {
+	_, err := this.pendingRestartsCollection().InsertOne(this.getTimeoutContext(), restart)
+	return err
+}
+
+// ClaimPendingRestart returns the oldest due restart and postpones it to lockUntil,
+// so that other instances do not run it at the same time
+func (this *mongoclient) ClaimPendingRestart(now time.Time, lockUntil time.Time) (restart messages.PendingRestart, found bool, err error) {
+	filter := bson.M{"due": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"due": lockUntil}}
+	option := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "due", Value: 1}}).
+		SetReturnDocument(options.After)
+	err = this.pendingRestartsCollection().FindOneAndUpdate(this.getTimeoutContext(), filter, update, option).Decode(&restart)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return restart, false, nil
+	}
+	if err != nil {
+		return restart, false, err
+	}
+	return restart, true, nil
+}
+
+func (this *mongoclient) RemovePendingRestart(id string) error {
+	_, err := this.pendingRestartsCollection().DeleteOne(this.getTimeoutContext(), bson.M{PendingRestartBson.Id: id})
+	return err
+}
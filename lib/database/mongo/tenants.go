@@ -0,0 +1,53 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"errors"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var TenantSettingsBson = getBsonFieldObject[messages.TenantSettings]()
+
+func (this *mongoclient) tenantSettingsCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoTenantSettingsCollectionName)
+}
+
+func (this *mongoclient) SetTenantSettings(settings messages.TenantSettings) error {
+	_, err := this.tenantSettingsCollection().ReplaceOne(this.getTimeoutContext(), bson.M{TenantSettingsBson.TenantId: settings.TenantId}, settings, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (this *mongoclient) GetTenantSettings(tenantId string) (settings messages.TenantSettings, exists bool, err error) {
+	err = this.tenantSettingsCollection().FindOne(this.getTimeoutContext(), bson.M{TenantSettingsBson.TenantId: tenantId}).Decode(&settings)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return settings, false, nil
+	}
+	if err != nil {
+		return settings, false, err
+	}
+	return settings, true, nil
+}
+
+func (this *mongoclient) DeleteTenantSettings(tenantId string) error {
+	_, err := this.tenantSettingsCollection().DeleteOne(this.getTimeoutContext(), bson.M{TenantSettingsBson.TenantId: tenantId})
+	return err
+}
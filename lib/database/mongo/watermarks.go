@@ -0,0 +1,53 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ShardWatermark struct {
+	Shard     string    `bson:"shard"`
+	Watermark time.Time `bson:"watermark"`
+}
+
+var ShardWatermarkBson = getBsonFieldObject[ShardWatermark]()
+
+func (this *mongoclient) watermarksCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoDatabaseName).Collection(this.config.MongoWatermarkCollectionName)
+}
+
+// GetShardWatermark returns the timestamp of the latest handled camunda incident of the shard; zero if unknown
+func (this *mongoclient) GetShardWatermark(shard string) (watermark time.Time, err error) {
+	result := ShardWatermark{}
+	err = this.watermarksCollection().FindOne(this.getTimeoutContext(), bson.M{ShardWatermarkBson.Shard: shard}).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return watermark, nil
+	}
+	return result.Watermark, err
+}
+
+// SetShardWatermark stores the watermark, if it is newer than the stored one
+func (this *mongoclient) SetShardWatermark(shard string, watermark time.Time) error {
+	_, err := this.watermarksCollection().UpdateOne(this.getTimeoutContext(), bson.M{ShardWatermarkBson.Shard: shard}, bson.M{"$max": bson.M{"watermark": watermark}}, options.Update().SetUpsert(true))
+	return err
+}
@@ -18,6 +18,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
 	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
@@ -25,21 +26,78 @@ import (
 
 type Controller interface {
 	GetIncident(token string, id string) (incident messages.IncidentMessage, err error, errCode int)
-	FindIncidents(token string, externalTaskId string, processDefinitionId string, processInstanceId string, limit int, offset int, sortBy string, asc bool) (incidents []messages.IncidentMessage, err error, errCode int)
+	GetIncidentDetails(token string, id string) (incident messages.IncidentMessage, err error, errCode int)
+	FindIncidents(token string, options messages.FindIncidentsOptions) (incidents []messages.IncidentMessage, nextCursor string, err error, errCode int)
+	CountIncidents(token string, filter messages.IncidentFilter) (count int64, err error, errCode int)
 	CreateIncident(token string, incident messages.Incident) (err error, code int)
+	SetIncidentStatus(token string, id string, status string) (err error, code int)
+	AddIncidentComment(token string, incidentId string, comment messages.IncidentComment) (result messages.IncidentComment, err error, code int)
+	ListIncidentComments(token string, incidentId string) (comments []messages.IncidentComment, err error, code int)
 	SetOnIncidentHandler(token string, incident messages.OnIncident) (err error, code int)
+	GetOnIncidentHandler(token string, processDefinitionId string) (handler messages.OnIncident, err error, code int)
+	ListOnIncidentHandlers(token string, limit int, offset int) (handlers []messages.OnIncident, err error, code int)
+	DeleteOnIncidentHandler(token string, processDefinitionId string) (err error, code int)
 	DeleteIncidentByProcessInstanceId(token string, id string) (err error, code int)
 	DeleteIncidentByProcessDefinitionId(token string, id string) (err error, code int)
+
+	StartBackfill(token string, request messages.BackfillRequest) (job messages.BackfillJob, err error, code int)
+	GetBackfill(token string, id string) (job messages.BackfillJob, err error, code int)
+	ListBackfills(token string) (jobs []messages.BackfillJob, err error, code int)
+
+	SetNotificationChannel(token string, channel messages.NotificationChannel) (result messages.NotificationChannel, err error, code int)
+	GetNotificationChannel(token string, id string) (channel messages.NotificationChannel, err error, code int)
+	ListNotificationChannels(token string, limit int, offset int) (channels []messages.NotificationChannel, err error, code int)
+	DeleteNotificationChannel(token string, id string) (err error, code int)
+
+	SetTenantSettings(token string, settings messages.TenantSettings) (err error, code int)
+	GetTenantSettings(token string, tenantId string) (settings messages.TenantSettings, err error, code int)
+	DeleteTenantSettings(token string, tenantId string) (err error, code int)
 }
 
 type Database interface {
 	GetIncidents(id string, user string) (incident messages.IncidentMessage, exists bool, err error)
-	FindIncidents(externalTaskId string, processDefinitionId string, processInstanceId string, limit int, offset int, sortBy string, asc bool, user string) (incidents []messages.IncidentMessage, err error)
+	FindIncidents(filter messages.IncidentFilter, cursor *messages.IncidentCursor, limit int, offset int, sortBy string, asc bool, user string) (incidents []messages.IncidentMessage, err error)
+	CountIncidents(filter messages.IncidentFilter, user string) (count int64, err error)
 	DeleteByDefinitionId(id string) error
 	SaveIncident(incident messages.Incident) error
+	InsertIncidentIfMissing(incident messages.Incident) (inserted bool, err error)
+	AddIncidentOccurrence(id string, lastSeen time.Time) error
+	SetIncidentStatus(id string, user string, status string, changedBy string, changedAt time.Time) (exists bool, err error)
 	DeleteIncidentByInstanceId(id string) error
+	SaveIncidentComment(comment messages.IncidentComment) error
+	ListIncidentComments(incidentId string, user string) (comments []messages.IncidentComment, err error)
 	SaveOnIncident(handler messages.OnIncident) error
 	GetOnIncident(definitionId string) (incident messages.OnIncident, exists bool, err error)
+	ListOnIncidents(tenantId string, limit int, offset int) (handlers []messages.OnIncident, err error)
+	DeleteOnIncidentByDefinitionId(definitionId string) error
+	IncrementOnIncidentRestartCount(definitionId string, now time.Time, window time.Duration) (handler messages.OnIncident, exists bool, err error)
+	DisableOnIncidentRestart(definitionId string) error
+	SavePendingRestart(restart messages.PendingRestart) error
+	ClaimPendingRestart(now time.Time, lockUntil time.Time) (restart messages.PendingRestart, found bool, err error)
+	RemovePendingRestart(id string) error
+	SaveBackfillJob(job messages.BackfillJob, expiresAt time.Time) error
+	GetBackfillJob(id string) (job messages.BackfillJob, exists bool, err error)
+	ListBackfillJobs() (jobs []messages.BackfillJob, err error)
+	TryLease(name string, owner string, value string, duration time.Duration) (acquired bool, current messages.Lease, err error)
+	ReleaseLease(name string, owner string) error
+	GetShardWatermark(shard string) (watermark time.Time, err error)
+	SetShardWatermark(shard string, watermark time.Time) error
+	AddCamundaIncidentFailure(failure messages.CamundaIncidentFailure) (attempts int64, err error)
+	EnqueueNotification(entry messages.OutboxEntry) error
+	ClaimNotification(now time.Time, lockUntil time.Time) (entry messages.OutboxEntry, found bool, err error)
+	UpdateNotification(entry messages.OutboxEntry) error
+	RemoveNotification(id string) error
+	SaveNotificationChannel(channel messages.NotificationChannel) error
+	GetNotificationChannel(id string) (channel messages.NotificationChannel, exists bool, err error)
+	ListNotificationChannels(tenantId string, limit int, offset int) (channels []messages.NotificationChannel, err error)
+	FindIncidentNotificationChannels(tenantId string, processDefinitionId string) (channels []messages.NotificationChannel, err error)
+	DeleteNotificationChannel(id string) error
+	SetTenantSettings(settings messages.TenantSettings) error
+	GetTenantSettings(tenantId string) (settings messages.TenantSettings, exists bool, err error)
+	DeleteTenantSettings(tenantId string) error
+	AddToNotificationDigest(tenantId string, incident messages.DigestIncident, due time.Time, notBefore time.Time, interval string) error
+	TakeDueNotificationDigest(now time.Time) (digest messages.NotificationDigest, found bool, err error)
+	IncrementNotificationRateCount(tenantId string, now time.Time, window time.Duration) (count int64, err error)
 }
 
 type DatabaseFactory interface {
@@ -59,6 +117,14 @@ type Camunda interface {
 	GetProcessName(id string, tenantId string) (string, error)
 	StartProcess(processDefinitionId string, userId string) (err error)
 	StartProcessWithBusinessKey(processDefinitionId string, businessKey string, userId string) (err error)
-	GetIncidents() (result []messages.CamundaIncident, err error)
+	StartProcessWithVariables(processDefinitionId string, businessKey string, variables map[string]messages.CamundaVariable, userId string) (err error)
+	RetryIncident(incident messages.Incident, retries int64, userId string) (err error)
+	RestartIncidentActivity(incident messages.Incident, userId string) (err error)
+	GetJobStacktrace(jobId string, maxLength int64, userId string) (stacktrace string, err error)
+	GetShards() (result []string, err error)
+	GetShardHistoricIncidents(shard string, from time.Time, until time.Time, firstResult int64, maxResults int64) (result []messages.CamundaHistoricIncident, err error)
+	GetShardIncidentsAfter(shard string, after time.Time, firstResult int64, maxResults int64) (result []messages.CamundaIncident, err error)
 	GetHistoricProcessInstance(id string, userId string) (result messages.HistoricProcessInstance, err error)
+	GetStartVariables(processInstanceId string, processDefinitionId string, userId string) (result map[string]messages.CamundaVariable, err error)
+	CheckProcessDefinitionAccess(processDefinitionId string, userId string) (allowed bool, err error)
 }
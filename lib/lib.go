@@ -26,6 +26,7 @@ import (
 	"github.com/SENERGY-Platform/process-incident-api/lib/database"
 	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
 	"github.com/SENERGY-Platform/process-incident-api/lib/metrics"
+	"github.com/SENERGY-Platform/process-incident-api/lib/notification"
 )
 
 func Start(ctx context.Context, config configuration.Config) (err error) {
@@ -55,7 +56,22 @@ func StartWith(parentCtx context.Context, config configuration.Config, api inter
 		cancel()
 		return err
 	}
-	err = camundasource.Start(ctx, config, camundaInstance, ctrl)
+	err = notification.StartDispatcher(ctx, config, databaseInstance, m)
+	if err != nil {
+		cancel()
+		return err
+	}
+	err = ctrl.StartDigests(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	err = ctrl.StartPendingRestarts(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	err = camundasource.Start(ctx, config, camundaInstance, databaseInstance, ctrl, m)
 	if err != nil {
 		cancel()
 		return err
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package messages
+
+import "time"
+
+type BackfillRequest struct {
+	Shards []string  `json:"shards,omitempty"` //optional; defaults to all shards
+	From   time.Time `json:"from"`             //optional; incidents created after this time
+	Until  time.Time `json:"until"`            //optional; incidents created before this time
+}
+
+const (
+	BackfillStatusRunning  = "running"
+	BackfillStatusFinished = "finished"
+	BackfillStatusFailed   = "failed"
+)
+
+type BackfillJob struct {
+	Id       string            `json:"id"`
+	Request  BackfillRequest   `json:"request"`
+	Status   string            `json:"status"`
+	Started  time.Time         `json:"started"`
+	Finished time.Time         `json:"finished,omitzero"`
+	Updated  time.Time         `json:"updated"`          //time of the last stored progress; running jobs without progress for a long time are marked as failed
+	Read     int64             `json:"read"`             //count of historic incidents read from camunda
+	Inserted int64             `json:"inserted"`         //count of incidents stored by this job; open incidents are handled like incidents reported by camunda
+	Skipped  int64             `json:"skipped"`          //count of incidents that were already stored
+	Invalid  int64             `json:"invalid"`          //count of incidents that could not be stored because of missing data, e.g. without tenant
+	Errors   map[string]string `json:"errors,omitempty"` //error by shard; "job" for errors of the whole job
+}
+
+type CamundaHistoricIncident struct {
+	Id                  string `json:"id"`
+	ProcessDefinitionId string `json:"processDefinitionId"`
+	ProcessInstanceId   string `json:"processInstanceId"`
+	ExecutionId         string `json:"executionId"`
+	CreateTime          string `json:"createTime"`
+	EndTime             string `json:"endTime"`
+	IncidentType        string `json:"incidentType"`
+	ActivityId          string `json:"activityId"`
+	CauseIncidentId     string `json:"causeIncidentId"`
+	RootCauseIncidentId string `json:"rootCauseIncidentId"`
+	Configuration       string `json:"configuration"`
+	TenantId            string `json:"tenantId"`
+	IncidentMessage     string `json:"incidentMessage"`
+	JobDefinitionId     string `json:"jobDefinitionId"`
+	Open                bool   `json:"open"`
+	Deleted             bool   `json:"deleted"`
+	Resolved            bool   `json:"resolved"`
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package messages
+
+const (
+	NotificationChannelTypeWebhook = "webhook"
+	NotificationChannelTypeEmail   = "email"
+	NotificationChannelTypeChat    = "chat" //slack/mattermost compatible incoming webhook
+)
+
+// NotificationChannel is an additional destination for incident notifications of a tenant;
+// if ProcessDefinitionId is set, only incidents of this process-definition are sent to the channel
+type NotificationChannel struct {
+	Id                  string   `json:"id" bson:"id"`
+	Name                string   `json:"name,omitempty" bson:"name,omitempty"`
+	TenantId            string   `json:"tenant_id" bson:"tenant_id"`
+	ProcessDefinitionId string   `json:"process_definition_id,omitempty" bson:"process_definition_id,omitempty"`
+	Type                string   `json:"type" bson:"type"`
+	Url                 string   `json:"url,omitempty" bson:"url,omitempty"`               //webhook and chat
+	Secret              string   `json:"secret,omitempty" bson:"secret,omitempty"`         //webhook; used to sign the payload with hmac-sha256; never returned by the api
+	Recipients          []string `json:"recipients,omitempty" bson:"recipients,omitempty"` //email
+}
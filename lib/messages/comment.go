@@ -0,0 +1,30 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package messages
+
+import "time"
+
+type IncidentComment struct {
+	Id                  string    `json:"id" bson:"id"`
+	IncidentId          string    `json:"incident_id" bson:"incident_id"`
+	ProcessInstanceId   string    `json:"process_instance_id" bson:"process_instance_id"`
+	ProcessDefinitionId string    `json:"process_definition_id" bson:"process_definition_id"`
+	TenantId            string    `json:"tenant_id" bson:"tenant_id"`
+	UserId              string    `json:"user_id" bson:"user_id"`
+	Text                string    `json:"text" bson:"text"`
+	Time                time.Time `json:"time" bson:"time"`
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package messages
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// IncidentCursor marks the position of the last incident of a page.
+// it is handed to clients as an opaque token (see Encode and ParseIncidentCursor)
+type IncidentCursor struct {
+	SortBy string `json:"s"`
+	Asc    bool   `json:"a"`
+	Value  string `json:"v"`
+	Id     string `json:"i"`
+}
+
+// NextIncidentCursor returns the encoded cursor of the page following incidents, which was requested with options;
+// it is empty if incidents is not a full page
+func NextIncidentCursor(incidents []Incident, options FindIncidentsOptions) string {
+	if options.Limit <= 0 || len(incidents) < options.Limit {
+		return ""
+	}
+	return newIncidentCursor(incidents[len(incidents)-1], options.SortBy, options.Asc).Encode()
+}
+
+// newIncidentCursor creates the cursor pointing behind the given incident, for a list sorted by sortBy in the given direction
+func newIncidentCursor(incident Incident, sortBy string, asc bool) IncidentCursor {
+	result := IncidentCursor{SortBy: sortBy, Asc: asc, Id: incident.Id}
+	switch sortBy {
+	case "id":
+		result.Value = incident.Id
+	case "external_task_id":
+		result.Value = incident.ExternalTaskId
+	case "process_instance_id":
+		result.Value = incident.ProcessInstanceId
+	case "process_definition_id":
+		result.Value = incident.ProcessDefinitionId
+	case "time":
+		result.Value = incident.Time.Format(time.RFC3339Nano)
+	}
+	return result
+}
+
+func (this IncidentCursor) Encode() string {
+	b, _ := json.Marshal(this)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// TypedValue returns Value with the type used in the database for the SortBy field
+func (this IncidentCursor) TypedValue() (interface{}, error) {
+	if this.SortBy == "time" {
+		return time.Parse(time.RFC3339Nano, this.Value)
+	}
+	return this.Value, nil
+}
+
+func ParseIncidentCursor(str string) (result IncidentCursor, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return result, errors.New("invalid cursor")
+	}
+	err = json.Unmarshal(b, &result)
+	if err != nil {
+		return result, errors.New("invalid cursor")
+	}
+	if result.SortBy == "" {
+		return result, errors.New("invalid cursor")
+	}
+	_, err = result.TypedValue()
+	if err != nil {
+		return result, errors.New("invalid cursor")
+	}
+	return result, nil
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package messages
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIncidentCursor(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC)
+	incident := Incident{Id: "id", ProcessInstanceId: "piid", Time: now}
+
+	cursor, err := ParseIncidentCursor(newIncidentCursor(incident, "time", true).Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := cursor.TypedValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !value.(time.Time).Equal(now) || cursor.Id != "id" || cursor.SortBy != "time" || !cursor.Asc {
+		t.Fatal(cursor, value)
+	}
+
+	cursor, err = ParseIncidentCursor(newIncidentCursor(incident, "process_instance_id", false).Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Value != "piid" || cursor.Id != "id" || cursor.Asc {
+		t.Fatal(cursor)
+	}
+
+	_, err = ParseIncidentCursor("foo")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
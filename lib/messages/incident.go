@@ -31,12 +31,69 @@ type Incident struct {
 	TenantId            string    `json:"tenant_id" bson:"tenant_id"`
 	DeploymentName      string    `json:"deployment_name" bson:"deployment_name"`
 	BusinessKey         string    `json:"business_key" bson:"business_key"`
+	Status              string    `json:"status,omitempty" bson:"status,omitempty"` //empty status is equivalent to IncidentStatusOpen
+	StatusChangedBy     string    `json:"status_changed_by,omitempty" bson:"status_changed_by,omitempty"`
+	StatusChangedAt     time.Time `json:"status_changed_at,omitzero" bson:"status_changed_at,omitempty"`
+	OccurrenceCount     int64     `json:"occurrence_count,omitempty" bson:"occurrence_count,omitempty"` //count of reports of this incident including deduplicated ones; empty = 1
+	LastSeen            time.Time `json:"last_seen,omitzero" bson:"last_seen,omitempty"`                //time of the last deduplicated report
+	Severity            string    `json:"severity,omitempty" bson:"severity,omitempty"`                 //one of the Severity constants; derived from the severity rules of the tenant
+
+	//metadata of incidents loaded from camunda
+	IncidentType        string `json:"incident_type,omitempty" bson:"incident_type,omitempty"` //e.g. failedJob or failedExternalTask
+	ActivityId          string `json:"activity_id,omitempty" bson:"activity_id,omitempty"`
+	CauseIncidentId     string `json:"cause_incident_id,omitempty" bson:"cause_incident_id,omitempty"`
+	RootCauseIncidentId string `json:"root_cause_incident_id,omitempty" bson:"root_cause_incident_id,omitempty"`
+	JobDefinitionId     string `json:"job_definition_id,omitempty" bson:"job_definition_id,omitempty"`
+	Configuration       string `json:"configuration,omitempty" bson:"configuration,omitempty"` //job id for failedJob, external task id for failedExternalTask
+	StackTrace          string `json:"stack_trace,omitempty" bson:"stack_trace,omitempty"`     //only returned by GET /incidents/{id}?details=true
+}
+
+const (
+	IncidentStatusOpen         = "open"
+	IncidentStatusAcknowledged = "acknowledged"
+	IncidentStatusResolved     = "resolved"
+)
+
+type IncidentStatusUpdate struct {
+	Status string `json:"status"`
 }
 
 type OnIncident struct {
 	ProcessDefinitionId string `json:"process_definition_id" bson:"process_definition_id"`
 	Restart             bool   `json:"restart" bson:"restart"`
 	Notify              bool   `json:"notify" bson:"notify"`
+	TenantId            string `json:"tenant_id,omitempty" bson:"tenant_id,omitempty"` //set to the owning user if the handler is not created by an admin
+
+	MaxRestarts       int64  `json:"max_restarts,omitempty" bson:"max_restarts,omitempty"`               //max count of restarts within restart_window; 0 = unlimited; restart is disabled if exceeded
+	RestartWindow     string `json:"restart_window,omitempty" bson:"restart_window,omitempty"`           //duration like "1h"; defaults to 1h
+	RestartBackoff    string `json:"restart_backoff,omitempty" bson:"restart_backoff,omitempty"`         //delay of the first restart within restart_window, doubled for every further restart; empty = no delay
+	MaxRestartBackoff string `json:"max_restart_backoff,omitempty" bson:"max_restart_backoff,omitempty"` //upper limit for the restart delay; defaults to 1h
+
+	RestartMode                string `json:"restart_mode,omitempty" bson:"restart_mode,omitempty"`                                   //one of the OnIncidentRestartMode constants; defaults to OnIncidentRestartModeProcess
+	RestartRetries             int64  `json:"restart_retries,omitempty" bson:"restart_retries,omitempty"`                             //retries set on the failed job or external-task in OnIncidentRestartModeRetry; defaults to 1
+	RestartWithStartParameters bool   `json:"restart_with_start_parameters,omitempty" bson:"restart_with_start_parameters,omitempty"` //restart with the start-parameters of the failed process-instance
+
+	KeepAlive bool `json:"keep_alive,omitempty" bson:"keep_alive,omitempty"` //the process-instance is not stopped; the incident is only stored and notified
+
+	DeduplicationWindow string `json:"deduplication_window,omitempty" bson:"deduplication_window,omitempty"` //duration in which further incidents of the same process-instance are only counted; overrides the global config; "0s" disables deduplication
+
+	Digest bool `json:"digest,omitempty" bson:"digest,omitempty"` //incidents are collected in the digest of the tenant instead of being notified immediately
+
+	//restart state, managed by the service
+	RestartCount       int64     `json:"restart_count,omitempty" bson:"restart_count,omitempty"`
+	RestartWindowStart time.Time `json:"restart_window_start,omitzero" bson:"restart_window_start,omitempty"`
+}
+
+const (
+	OnIncidentRestartModeProcess  = "process"  //stop the process-instance and start a new one
+	OnIncidentRestartModeRetry    = "retry"    //keep the process-instance and reset the retries of the failed job or external-task
+	OnIncidentRestartModeActivity = "activity" //keep the process-instance and restart it before the failed activity
+)
+
+type CamundaVariable struct {
+	Value     interface{} `json:"value"`
+	Type      string      `json:"type,omitempty"`
+	ValueInfo interface{} `json:"valueInfo,omitempty"`
 }
 
 type CamundaIncident struct {
@@ -55,6 +112,29 @@ type CamundaIncident struct {
 	JobDefinitionId     string `json:"jobDefinitionId"`
 }
 
+const (
+	CamundaIncidentTypeFailedJob          = "failedJob"
+	CamundaIncidentTypeFailedExternalTask = "failedExternalTask"
+)
+
+const CamundaTimeFormat = "2006-01-02T15:04:05.000-0700"
+
+func (this CamundaIncident) Timestamp() (time.Time, error) {
+	return time.Parse(CamundaTimeFormat, this.IncidentTimestamp)
+}
+
+// CamundaIncidentFailure counts the failed attempts to handle a polled camunda incident;
+// after camunda_incident_max_attempts the incident is no longer retried and the shard watermark advances past it
+type CamundaIncidentFailure struct {
+	IncidentId  string    `json:"incident_id" bson:"incident_id"`
+	Shard       string    `json:"shard" bson:"shard"`
+	Timestamp   time.Time `json:"timestamp" bson:"timestamp"` //incident timestamp
+	Attempts    int64     `json:"attempts" bson:"attempts"`
+	LastError   string    `json:"last_error" bson:"last_error"`
+	LastAttempt time.Time `json:"last_attempt" bson:"last_attempt"`
+	ExpiresAt   time.Time `json:"expires_at" bson:"expires_at"`
+}
+
 type HistoricProcessInstance struct {
 	Id                       string  `json:"id"`
 	SuperProcessInstanceId   string  `json:"superProcessInstanceId"`
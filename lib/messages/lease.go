@@ -0,0 +1,27 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package messages
+
+import "time"
+
+// Lease is a named, time limited claim shared between all instances of the service
+type Lease struct {
+	Name      string    `json:"name" bson:"name"`
+	Owner     string    `json:"owner" bson:"owner"`
+	Value     string    `json:"value" bson:"value"`
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package messages
+
+import "time"
+
+const (
+	OutboxStatusPending    = "pending"
+	OutboxStatusDeadLetter = "dead_letter"
+)
+
+// OutboxEntry is a notification waiting for delivery to the platform notifier or a notification channel; delivered entries are removed,
+// dead-lettered entries are removed by the database after ExpiresAt
+type OutboxEntry struct {
+	Id                  string    `json:"id" bson:"id"`
+	ChannelId           string    `json:"channel_id,omitempty" bson:"channel_id,omitempty"` //empty for the platform notifier
+	IncidentId          string    `json:"incident_id,omitempty" bson:"incident_id,omitempty"`
+	ProcessDefinitionId string    `json:"process_definition_id,omitempty" bson:"process_definition_id,omitempty"`
+	ProcessInstanceId   string    `json:"process_instance_id,omitempty" bson:"process_instance_id,omitempty"`
+	DeploymentName      string    `json:"deployment_name,omitempty" bson:"deployment_name,omitempty"`
+	Severity            string    `json:"severity,omitempty" bson:"severity,omitempty"`
+	UserId              string    `json:"user_id" bson:"user_id"`
+	Title               string    `json:"title" bson:"title"`
+	Message             string    `json:"message" bson:"message"`
+	Topic               string    `json:"topic" bson:"topic"`
+	Status              string    `json:"status" bson:"status"`
+	Attempts            int64     `json:"attempts" bson:"attempts"`
+	NextAttempt         time.Time `json:"next_attempt" bson:"next_attempt"`
+	LastError           string    `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	Created             time.Time `json:"created" bson:"created"`
+	ExpiresAt           time.Time `json:"expires_at,omitzero" bson:"expires_at,omitempty"` //only set for dead-lettered entries
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package messages
+
+import "time"
+
+// IncidentFilter selects incidents for lists and counts; empty fields are ignored
+type IncidentFilter struct {
+	ExternalTaskId      string
+	ProcessDefinitionId string
+	ProcessInstanceId   string
+	From                time.Time
+	Until               time.Time
+	Search              string
+	Status              string
+}
+
+// FindIncidentsOptions describes a page of incidents; Offset is ignored if Cursor is set
+type FindIncidentsOptions struct {
+	IncidentFilter
+	Cursor string
+	Limit  int
+	Offset int
+	SortBy string
+	Asc    bool
+}
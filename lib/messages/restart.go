@@ -0,0 +1,28 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package messages
+
+import "time"
+
+// PendingRestart is a delayed restart of an incidents process; it is stored until due so that it survives restarts of the service.
+// the handler is loaded when the restart is run, so that changes of the handler during the delay are respected
+type PendingRestart struct {
+	Id        string    `json:"id" bson:"id"`
+	Due       time.Time `json:"due" bson:"due"`
+	HandlerId string    `json:"handler_id" bson:"handler_id"` //process_definition_id of the on-incident handler
+	Incident  Incident  `json:"incident" bson:"incident"`
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package messages
+
+import "time"
+
+// TenantSettings contains settings of a tenant (user), that are used for its incident notifications
+type TenantSettings struct {
+	TenantId  string                          `json:"tenant_id" bson:"tenant_id"`
+	Locale    string                          `json:"locale,omitempty" bson:"locale,omitempty"`       //locale of notifications; defaults to the configured notification_default_locale
+	Templates map[string]NotificationTemplate `json:"templates,omitempty" bson:"templates,omitempty"` //overrides the templates of the locale by template name (incident, restart_notice, restart_failed, restart_disabled, digest)
+
+	DigestInterval  string `json:"digest_interval,omitempty" bson:"digest_interval,omitempty"`     //if set, all incidents are collected and notified as one digest per interval (e.g. "15m"); otherwise only incidents of handlers with digest and incidents exceeding the rate limit
+	RateLimit       int64  `json:"rate_limit,omitempty" bson:"rate_limit,omitempty"`               //max count of immediate incident notifications within rate_limit_window; further incidents are collected in the digest; 0 = configured default; -1 = unlimited
+	RateLimitWindow string `json:"rate_limit_window,omitempty" bson:"rate_limit_window,omitempty"` //defaults to the configured notification_rate_limit_window
+
+	SeverityRules []SeverityRule `json:"severity_rules,omitempty" bson:"severity_rules,omitempty"` //the first matching rule sets the severity of an incident; incidents without matching rule get the configured incident_default_severity
+	QuietHours    *QuietHours    `json:"quiet_hours,omitempty" bson:"quiet_hours,omitempty"`
+}
+
+const (
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// SeverityRule matches incidents, if all set conditions match
+type SeverityRule struct {
+	ProcessDefinitionId string `json:"process_definition_id,omitempty" bson:"process_definition_id,omitempty"`
+	IncidentType        string `json:"incident_type,omitempty" bson:"incident_type,omitempty"`
+	ErrorMessagePattern string `json:"error_message_pattern,omitempty" bson:"error_message_pattern,omitempty"` //regular expression
+	Severity            string `json:"severity" bson:"severity"`
+}
+
+const (
+	QuietHoursActionSuppress = "suppress" //the incident is stored but not notified
+	QuietHoursActionDelay    = "delay"    //the notification is sent at the end of the quiet hours
+)
+
+// QuietHours define a daily time range, in which incident notifications are routed by severity
+type QuietHours struct {
+	Start    string            `json:"start" bson:"start"`                             //local time like "22:00"
+	End      string            `json:"end" bson:"end"`                                 //local time like "07:00"; may be before start to span midnight
+	TimeZone string            `json:"time_zone,omitempty" bson:"time_zone,omitempty"` //IANA time zone like "Europe/Berlin"; defaults to UTC
+	Routing  map[string]string `json:"routing" bson:"routing"`                         //QuietHoursAction by severity; severities without action are notified immediately
+}
+
+// NotificationDigest collects the incidents of a tenant, until they are notified at Due
+type NotificationDigest struct {
+	TenantId  string           `json:"tenant_id" bson:"tenant_id"`
+	Since     time.Time        `json:"since" bson:"since"`
+	Due       time.Time        `json:"due" bson:"due"`
+	Interval  string           `json:"interval" bson:"interval"`
+	Count     int64            `json:"count" bson:"count"`
+	Processes []string         `json:"processes" bson:"processes"` //deployment names
+	Incidents []DigestIncident `json:"incidents" bson:"incidents"` //the latest incidents; limited to DigestMaxIncidents
+}
+
+const DigestMaxIncidents = 20
+
+type DigestIncident struct {
+	Id                  string    `json:"id" bson:"id"`
+	ProcessDefinitionId string    `json:"process_definition_id" bson:"process_definition_id"`
+	ProcessInstanceId   string    `json:"process_instance_id" bson:"process_instance_id"`
+	DeploymentName      string    `json:"deployment_name" bson:"deployment_name"`
+	ErrorMessage        string    `json:"error_message" bson:"error_message"`
+	Time                time.Time `json:"time" bson:"time"`
+}
+
+// NotificationTemplate contains text/template templates for the title and message of a notification
+type NotificationTemplate struct {
+	Title   string `json:"title" bson:"title"`
+	Message string `json:"message" bson:"message"`
+}
@@ -23,11 +23,17 @@ import (
 	"log"
 	"net/http"
 	"runtime/debug"
+	"time"
 )
 
 type Metrics struct {
-	IncidentMessages prometheus.Counter
-	httphandler      http.Handler
+	IncidentMessages               prometheus.Counter
+	CamundaShardPolls              *prometheus.CounterVec
+	CamundaShardIncidents          *prometheus.GaugeVec
+	CamundaShardPollDurationMs     *prometheus.GaugeVec
+	CamundaShardLastSuccessfulPoll *prometheus.GaugeVec
+	NotificationDispatches         *prometheus.CounterVec
+	httphandler                    http.Handler
 }
 
 func New() *Metrics {
@@ -43,9 +49,34 @@ func New() *Metrics {
 			Name: "incident_worker_incident_messages",
 			Help: "count of incident messages received since startup",
 		}),
+		CamundaShardPolls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "incident_worker_camunda_shard_polls",
+			Help: "count of incident requests to camunda shards since startup",
+		}, []string{"shard", "result"}),
+		CamundaShardIncidents: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "incident_worker_camunda_shard_incidents",
+			Help: "count of new incidents loaded from camunda shard in the last successful request",
+		}, []string{"shard"}),
+		CamundaShardPollDurationMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "incident_worker_camunda_shard_poll_duration_ms",
+			Help: "duration of the last incident request to camunda shard in ms",
+		}, []string{"shard"}),
+		CamundaShardLastSuccessfulPoll: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "incident_worker_camunda_shard_last_successful_poll",
+			Help: "unix timestamp of the last successful incident request to camunda shard",
+		}, []string{"shard"}),
+		NotificationDispatches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "incident_worker_notification_dispatches",
+			Help: "count of notification delivery attempts from the outbox since startup",
+		}, []string{"result"}),
 	}
 
 	reg.MustRegister(m.IncidentMessages)
+	reg.MustRegister(m.CamundaShardPolls)
+	reg.MustRegister(m.CamundaShardIncidents)
+	reg.MustRegister(m.CamundaShardPollDurationMs)
+	reg.MustRegister(m.CamundaShardLastSuccessfulPoll)
+	reg.MustRegister(m.NotificationDispatches)
 
 	return m
 }
@@ -82,3 +113,23 @@ func (this *Metrics) NotifyIncidentMessage() {
 		this.IncidentMessages.Inc()
 	}
 }
+
+func (this *Metrics) NotifyCamundaShardPoll(shard string, duration time.Duration, incidents int, err error) {
+	if this == nil || this.CamundaShardPolls == nil {
+		return
+	}
+	this.CamundaShardPollDurationMs.WithLabelValues(shard).Set(float64(duration.Milliseconds()))
+	if err != nil {
+		this.CamundaShardPolls.WithLabelValues(shard, "error").Inc()
+		return
+	}
+	this.CamundaShardPolls.WithLabelValues(shard, "success").Inc()
+	this.CamundaShardIncidents.WithLabelValues(shard).Set(float64(incidents))
+	this.CamundaShardLastSuccessfulPoll.WithLabelValues(shard).Set(float64(time.Now().Unix()))
+}
+
+func (this *Metrics) NotifyNotificationDispatch(result string) {
+	if this != nil && this.NotificationDispatches != nil {
+		this.NotificationDispatches.WithLabelValues(result).Inc()
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"errors"
+	"net/mail"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+)
+
+type Channel interface {
+	Send(msg ChannelMessage) error
+}
+
+// ChannelMessage is the message with the context of the incident, that is sent to notification channels
+type ChannelMessage struct {
+	Message
+	IncidentId          string `json:"incidentId,omitempty"`
+	ProcessDefinitionId string `json:"processDefinitionId,omitempty"`
+	ProcessInstanceId   string `json:"processInstanceId,omitempty"`
+	DeploymentName      string `json:"deploymentName,omitempty"`
+	Severity            string `json:"severity,omitempty"`
+}
+
+func NewChannel(config configuration.Config, channel messages.NotificationChannel) (Channel, error) {
+	switch channel.Type {
+	case messages.NotificationChannelTypeWebhook:
+		return &WebhookChannel{Url: channel.Url, Secret: channel.Secret, Client: NewHostPolicy(config).getClient(channel.Url)}, nil
+	case messages.NotificationChannelTypeChat:
+		return &ChatChannel{Url: channel.Url, Client: NewHostPolicy(config).getClient(channel.Url)}, nil
+	case messages.NotificationChannelTypeEmail:
+		return &EmailChannel{
+			Host:       config.SmtpHost,
+			Port:       config.SmtpPort,
+			User:       config.SmtpUser,
+			Password:   config.SmtpPassword,
+			From:       config.SmtpFrom,
+			Recipients: channel.Recipients,
+		}, nil
+	default:
+		return nil, errors.New("unknown notification channel type " + channel.Type)
+	}
+}
+
+// PlatformChannel sends messages to the platform notifier
+type PlatformChannel struct {
+	Url string
+}
+
+func (this *PlatformChannel) Send(msg ChannelMessage) error {
+	return Send(this.Url, msg.Message)
+}
+
+func ValidateChannel(config configuration.Config, channel messages.NotificationChannel) error {
+	switch channel.Type {
+	case messages.NotificationChannelTypeWebhook, messages.NotificationChannelTypeChat:
+		return NewHostPolicy(config).CheckUrl(channel.Url)
+	case messages.NotificationChannelTypeEmail:
+		if config.SmtpHost == "" {
+			return errors.New("email channels are not configured")
+		}
+		if len(channel.Recipients) == 0 {
+			return errors.New("expect at least one recipient")
+		}
+		for _, recipient := range channel.Recipients {
+			_, err := mail.ParseAddress(recipient)
+			if err != nil {
+				return errors.New("invalid recipient " + recipient + ": " + err.Error())
+			}
+		}
+	default:
+		return errors.New("unknown notification channel type " + channel.Type)
+	}
+	return nil
+}
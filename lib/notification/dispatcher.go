@@ -0,0 +1,216 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+)
+
+type Metric interface {
+	NotifyNotificationDispatch(result string)
+}
+
+const (
+	DispatchResultDelivered  = "delivered"
+	DispatchResultRetry      = "retry"
+	DispatchResultDeadLetter = "dead_letter"
+	DispatchResultError      = "error"
+)
+
+const DefaultMaxAttempts = 10
+const DefaultRetryBaseDelay = 10 * time.Second
+const DefaultRetryMaxDelay = time.Hour
+const DefaultDeadLetterRetention = 30 * 24 * time.Hour
+
+// claimDuration is the time a claimed entry is hidden from other dispatchers; it must be longer than a delivery attempt
+const claimDuration = time.Minute
+
+type Dispatcher struct {
+	config          configuration.Config
+	notificationUrl string
+	db              interfaces.Database
+	metric          Metric
+	maxAttempts     int64
+	baseDelay       time.Duration
+	maxDelay        time.Duration
+	retention       time.Duration
+}
+
+// StartDispatcher delivers the notifications of the outbox in the configured interval.
+// every instance may run a dispatcher; entries are claimed in the database before delivery.
+// notifications for the platform notifier and for notification channels share the outbox
+func StartDispatcher(ctx context.Context, config configuration.Config, db interfaces.Database, m Metric) error {
+	if config.NotificationDispatchInterval == "" || config.NotificationDispatchInterval == "-" {
+		return nil
+	}
+	interval, err := time.ParseDuration(config.NotificationDispatchInterval)
+	if err != nil {
+		return err
+	}
+	dispatcher, err := NewDispatcher(config, db, m)
+	if err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				dispatcher.Dispatch()
+			}
+		}
+	}()
+	return nil
+}
+
+func NewDispatcher(config configuration.Config, db interfaces.Database, m Metric) (dispatcher *Dispatcher, err error) {
+	dispatcher = &Dispatcher{
+		config:          config,
+		notificationUrl: config.NotificationUrl,
+		db:              db,
+		metric:          m,
+		maxAttempts:     config.NotificationMaxAttempts,
+		baseDelay:       DefaultRetryBaseDelay,
+		maxDelay:        DefaultRetryMaxDelay,
+		retention:       DefaultDeadLetterRetention,
+	}
+	if dispatcher.maxAttempts < 1 {
+		dispatcher.maxAttempts = DefaultMaxAttempts
+	}
+	if config.NotificationRetryBaseDelay != "" {
+		dispatcher.baseDelay, err = time.ParseDuration(config.NotificationRetryBaseDelay)
+		if err != nil {
+			return dispatcher, err
+		}
+	}
+	if config.NotificationRetryMaxDelay != "" {
+		dispatcher.maxDelay, err = time.ParseDuration(config.NotificationRetryMaxDelay)
+		if err != nil {
+			return dispatcher, err
+		}
+	}
+	if config.NotificationDeadLetterRetention != "" {
+		dispatcher.retention, err = time.ParseDuration(config.NotificationDeadLetterRetention)
+		if err != nil {
+			return dispatcher, err
+		}
+	}
+	return dispatcher, nil
+}
+
+// Dispatch delivers all due entries of the outbox
+func (this *Dispatcher) Dispatch() {
+	for {
+		now := time.Now()
+		entry, found, err := this.db.ClaimNotification(now, now.Add(claimDuration))
+		if err != nil {
+			log.Println("ERROR: unable to claim notification from outbox", err)
+			this.metric.NotifyNotificationDispatch(DispatchResultError)
+			return
+		}
+		if !found {
+			return
+		}
+		this.deliver(entry)
+	}
+}
+
+func (this *Dispatcher) deliver(entry messages.OutboxEntry) {
+	channel, exists, err := this.getChannel(entry)
+	if err == nil && !exists {
+		log.Println("WARNING: notification channel removed, drop notification", entry.ChannelId, entry.Id)
+		err = this.db.RemoveNotification(entry.Id)
+		if err != nil {
+			log.Println("ERROR: unable to remove notification from outbox", entry.Id, err)
+		}
+		return
+	}
+	if err == nil {
+		err = channel.Send(ChannelMessage{
+			Message: Message{
+				UserId:  entry.UserId,
+				Title:   entry.Title,
+				Message: entry.Message,
+				Topic:   entry.Topic,
+			},
+			IncidentId:          entry.IncidentId,
+			ProcessDefinitionId: entry.ProcessDefinitionId,
+			ProcessInstanceId:   entry.ProcessInstanceId,
+			DeploymentName:      entry.DeploymentName,
+			Severity:            entry.Severity,
+		})
+	}
+	if err == nil {
+		this.metric.NotifyNotificationDispatch(DispatchResultDelivered)
+		err = this.db.RemoveNotification(entry.Id)
+		if err != nil {
+			//the entry will be delivered again after the claim expired
+			log.Println("ERROR: unable to remove delivered notification from outbox", entry.Id, err)
+		}
+		return
+	}
+	entry.Attempts = entry.Attempts + 1
+	entry.LastError = err.Error()
+	result := DispatchResultRetry
+	if entry.Attempts >= this.maxAttempts {
+		log.Println("WARNING: notification dead-lettered after", entry.Attempts, "attempts", entry.Id, entry.UserId, err)
+		entry.Status = messages.OutboxStatusDeadLetter
+		entry.ExpiresAt = time.Now().Add(this.retention)
+		result = DispatchResultDeadLetter
+	} else {
+		entry.NextAttempt = time.Now().Add(this.getRetryDelay(entry.Attempts))
+	}
+	this.metric.NotifyNotificationDispatch(result)
+	err = this.db.UpdateNotification(entry)
+	if err != nil {
+		log.Println("ERROR: unable to update notification in outbox", entry.Id, err)
+	}
+}
+
+// getChannel returns the platform notifier for entries without channel id
+func (this *Dispatcher) getChannel(entry messages.OutboxEntry) (channel Channel, exists bool, err error) {
+	if entry.ChannelId == "" {
+		return &PlatformChannel{Url: this.notificationUrl}, true, nil
+	}
+	config, exists, err := this.db.GetNotificationChannel(entry.ChannelId)
+	if err != nil || !exists {
+		return nil, exists, err
+	}
+	channel, err = NewChannel(this.config, config)
+	return channel, true, err
+}
+
+// getRetryDelay doubles the base delay for every failed attempt, limited by the max delay
+func (this *Dispatcher) getRetryDelay(attempts int64) time.Duration {
+	delay := this.baseDelay
+	for i := int64(1); i < attempts; i++ {
+		delay = delay * 2
+		if delay >= this.maxDelay {
+			return this.maxDelay
+		}
+	}
+	return min(delay, this.maxDelay)
+}
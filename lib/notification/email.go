@@ -0,0 +1,63 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// EmailChannel sends messages as plain text mails with the configured smtp server
+type EmailChannel struct {
+	Host       string
+	Port       string
+	User       string
+	Password   string
+	From       string
+	Recipients []string
+}
+
+func (this *EmailChannel) Send(msg ChannelMessage) error {
+	if this.Host == "" {
+		return errors.New("missing smtp host")
+	}
+	port := this.Port
+	if port == "" {
+		port = "25"
+	}
+	var auth smtp.Auth
+	if this.User != "" {
+		auth = smtp.PlainAuth("", this.User, this.Password, this.Host)
+	}
+	return smtp.SendMail(this.Host+":"+port, auth, this.From, this.Recipients, this.getMail(msg))
+}
+
+func (this *EmailChannel) getMail(msg ChannelMessage) []byte {
+	header := []string{
+		"From: " + this.From,
+		"To: " + strings.Join(this.Recipients, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Title),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+	}
+	return []byte(fmt.Sprintf("%v\r\n\r\n%v\r\n", strings.Join(header, "\r\n"), strings.ReplaceAll(msg.Message.Message, "\n", "\r\n")))
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+)
+
+// ErrForbiddenAddress is returned for channel urls, that resolve to loopback, private, link-local or otherwise internal addresses
+var ErrForbiddenAddress = errors.New("forbidden address")
+
+// sharedAddressSpace (rfc 6598) is not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// guardedClient refuses connections to internal addresses at dial time, so that dns changes and redirects can not bypass the validation of the url
+var guardedClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: func(network string, address string, c syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				ip := net.ParseIP(host)
+				if ip == nil || isInternalAddress(ip) {
+					return fmt.Errorf("%w: %v", ErrForbiddenAddress, host)
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+	},
+}
+
+// HostPolicy decides which hosts may be used by webhook and chat channels
+// hosts are matched by name; entries with a "*." prefix match all subdomains
+type HostPolicy struct {
+	allowed []string //may resolve to internal addresses
+	denied  []string //are never used
+}
+
+func NewHostPolicy(config configuration.Config) HostPolicy {
+	return HostPolicy{allowed: config.NotificationChannelAllowedHosts, denied: config.NotificationChannelDeniedHosts}
+}
+
+// CheckUrl returns an error, if the url is no http(s) url or if its host is denied or resolves to an internal address
+func (this HostPolicy) CheckUrl(rawUrl string) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return errors.New("invalid url: " + err.Error())
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("expect url with http or https scheme")
+	}
+	host := u.Hostname()
+	if matchHost(this.denied, host) {
+		return errors.New("host " + host + " is not allowed")
+	}
+	if matchHost(this.allowed, host) {
+		return nil
+	}
+	ips := []net.IP{}
+	if ip := net.ParseIP(host); ip != nil {
+		ips = append(ips, ip)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return errors.New("unable to resolve host " + host + ": " + err.Error())
+		}
+		for _, address := range addresses {
+			ips = append(ips, address.IP)
+		}
+	}
+	for _, ip := range ips {
+		if isInternalAddress(ip) {
+			return errors.New("host " + host + " resolves to an internal address")
+		}
+	}
+	return nil
+}
+
+// getClient returns http.DefaultClient for allowed hosts and guardedClient for all other hosts
+func (this HostPolicy) getClient(rawUrl string) *http.Client {
+	u, err := url.Parse(rawUrl)
+	if err == nil && matchHost(this.allowed, u.Hostname()) && !matchHost(this.denied, u.Hostname()) {
+		return http.DefaultClient
+	}
+	return guardedClient
+}
+
+func matchHost(patterns []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func isInternalAddress(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+)
+
+func TestHostPolicyCheckUrl(t *testing.T) {
+	policy := NewHostPolicy(configuration.Config{
+		NotificationChannelAllowedHosts: []string{"127.0.0.1", "*.internal.example.com"},
+		NotificationChannelDeniedHosts:  []string{"8.8.8.8"},
+	})
+	for rawUrl, allowed := range map[string]bool{
+		"http://127.0.0.1:8080/hook":            true,
+		"http://chat.internal.example.com/hook": true,
+		"https://1.1.1.1/hook":                  true,
+		"https://8.8.8.8/hook":                  false,
+		"http://127.0.0.2/hook":                 false,
+		"http://[::1]/hook":                     false,
+		"http://169.254.169.254/latest":         false,
+		"http://10.1.2.3/hook":                  false,
+		"http://192.168.0.1/hook":               false,
+		"http://100.100.100.200/hook":           false,
+		"http://0.0.0.0/hook":                   false,
+		"http://[fd00:ec2::254]/hook":           false,
+		"http://[::ffff:127.0.0.1]/hook":        false,
+		"ftp://1.1.1.1/hook":                    false,
+	} {
+		err := policy.CheckUrl(rawUrl)
+		if allowed && err != nil {
+			t.Error(rawUrl, err)
+		}
+		if !allowed && err == nil {
+			t.Error("expected error for", rawUrl)
+		}
+	}
+}
+
+func TestGuardedClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	defer server.Close()
+
+	err := post(NewHostPolicy(configuration.Config{}).getClient(server.URL), server.URL, []byte("{}"), http.Header{})
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Error(err)
+	}
+
+	host, _, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = post(NewHostPolicy(configuration.Config{NotificationChannelAllowedHosts: []string{host}}).getClient(server.URL), server.URL, []byte("{}"), http.Header{})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -42,14 +42,16 @@ func Send(notificationUrl string, message Message) error {
 		log.Println("ERROR: unable to send notification", err)
 		return err
 	}
-	ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
-	req.WithContext(ctx)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		log.Println("ERROR: unable to send notification", err)
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
 		respMsg, _ := io.ReadAll(resp.Body)
 		log.Println("ERROR: unexpected response status from notifier", resp.StatusCode, string(respMsg))
@@ -0,0 +1,289 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"text/template"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+)
+
+const (
+	TemplateIncident        = "incident"
+	TemplateRestartNotice   = "restart_notice" //only the message is used; it is available as .RestartNotice in the incident template
+	TemplateRestartFailed   = "restart_failed"
+	TemplateRestartDisabled = "restart_disabled"
+	TemplateDigest          = "digest"
+)
+
+const DefaultLocale = "en"
+
+// TemplateData is the data available in notification templates
+type TemplateData struct {
+	Incident      messages.Incident
+	RestartMode   string //process, retry or activity
+	RestartNotice string
+	Error         string
+	MaxRestarts   int64
+	RestartWindow string
+	Digest        messages.NotificationDigest
+}
+
+var DefaultTemplates = map[string]map[string]messages.NotificationTemplate{
+	"en": {
+		TemplateIncident: {
+			Title:   "Process-Incident in {{.Incident.DeploymentName}}",
+			Message: "{{.Incident.ErrorMessage}}{{if .RestartNotice}}\n\n{{.RestartNotice}}{{end}}",
+		},
+		TemplateRestartNotice: {
+			Message: `{{if eq .RestartMode "retry"}}failed activity will be retried{{else if eq .RestartMode "activity"}}failed activity will be restarted{{else}}process will be restarted{{end}}`,
+		},
+		TemplateRestartFailed: {
+			Title:   `ERROR: unable to restart {{if eq .RestartMode "process"}}process{{else}}failed activity{{end}} after incident in: {{.Incident.DeploymentName}}`,
+			Message: "Restart-Error: {{.Error}} \n\n Incident: {{.Incident.ErrorMessage}} \n",
+		},
+		TemplateRestartDisabled: {
+			Title:   "Automatic restart disabled for {{.Incident.DeploymentName}}",
+			Message: "The process has been restarted more than {{.MaxRestarts}} times within {{.RestartWindow}}. Automatic restart has been disabled.\n\nIncident: {{.Incident.ErrorMessage}}\n",
+		},
+		TemplateDigest: {
+			Title:   "{{.Digest.Count}} Process-Incident{{if ne .Digest.Count 1}}s{{end}} in {{len .Digest.Processes}} process{{if ne (len .Digest.Processes) 1}}es{{end}}",
+			Message: "{{.Digest.Count}} incident{{if ne .Digest.Count 1}}s{{end}} in {{len .Digest.Processes}} process{{if ne (len .Digest.Processes) 1}}es{{end}} in the last {{.Digest.Interval}}:\n{{range .Digest.Incidents}}\n{{.Time.Format \"2006-01-02 15:04:05\"}} {{.DeploymentName}}: {{.ErrorMessage}}{{end}}{{if gt .Digest.Count (len .Digest.Incidents)}}\n...{{end}}\n",
+		},
+	},
+	"de": {
+		TemplateIncident: {
+			Title:   "Prozess-Fehler in {{.Incident.DeploymentName}}",
+			Message: "{{.Incident.ErrorMessage}}{{if .RestartNotice}}\n\n{{.RestartNotice}}{{end}}",
+		},
+		TemplateRestartNotice: {
+			Message: `{{if eq .RestartMode "retry"}}die fehlgeschlagene Aktivität wird wiederholt{{else if eq .RestartMode "activity"}}die fehlgeschlagene Aktivität wird neu gestartet{{else}}der Prozess wird neu gestartet{{end}}`,
+		},
+		TemplateRestartFailed: {
+			Title:   `FEHLER: Neustart {{if eq .RestartMode "process"}}des Prozesses{{else}}der fehlgeschlagenen Aktivität{{end}} nach Fehler in {{.Incident.DeploymentName}} nicht möglich`,
+			Message: "Neustart-Fehler: {{.Error}} \n\n Fehler: {{.Incident.ErrorMessage}} \n",
+		},
+		TemplateRestartDisabled: {
+			Title:   "Automatischer Neustart für {{.Incident.DeploymentName}} deaktiviert",
+			Message: "Der Prozess wurde innerhalb von {{.RestartWindow}} mehr als {{.MaxRestarts}} mal neu gestartet. Der automatische Neustart wurde deaktiviert.\n\nFehler: {{.Incident.ErrorMessage}}\n",
+		},
+		TemplateDigest: {
+			Title:   "{{.Digest.Count}} Prozess-Fehler in {{len .Digest.Processes}} Prozess{{if ne (len .Digest.Processes) 1}}en{{end}}",
+			Message: "{{.Digest.Count}} Fehler in {{len .Digest.Processes}} Prozess{{if ne (len .Digest.Processes) 1}}en{{end}} in den letzten {{.Digest.Interval}}:\n{{range .Digest.Incidents}}\n{{.Time.Format \"2006-01-02 15:04:05\"}} {{.DeploymentName}}: {{.ErrorMessage}}{{end}}{{if gt .Digest.Count (len .Digest.Incidents)}}\n...{{end}}\n",
+		},
+	},
+}
+
+// maxCachedTenantTemplates limits the memory of tenant templates, that are no longer used by any tenant
+const maxCachedTenantTemplates = 1000
+
+// Templates renders notifications with the templates of the tenant, its locale or the default locale, in this order
+type Templates struct {
+	defaultLocale   string
+	templates       map[string]map[string]parsedTemplate
+	tenantTemplates templateCache
+}
+
+type parsedTemplate struct {
+	title   *template.Template
+	message *template.Template
+}
+
+// templateCache holds the parsed templates of tenant settings by their text, so that they are not parsed for every notification
+type templateCache struct {
+	mux       sync.Mutex
+	templates map[string]*template.Template
+}
+
+func (this *templateCache) parse(text string) (*template.Template, error) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if result, ok := this.templates[text]; ok {
+		return result, nil
+	}
+	result, err := template.New("").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if this.templates == nil || len(this.templates) >= maxCachedTenantTemplates {
+		this.templates = map[string]*template.Template{}
+	}
+	this.templates[text] = result
+	return result, nil
+}
+
+// LoadTemplates merges the DefaultTemplates with the templates of config.NotificationTemplatesFile and parses them.
+// the file contains a json object of templates by name by locale (e.g. {"de": {"incident": {"title": "...", "message": "..."}}})
+func LoadTemplates(config configuration.Config) (result *Templates, err error) {
+	result = &Templates{defaultLocale: config.NotificationDefaultLocale, templates: map[string]map[string]parsedTemplate{}}
+	if result.defaultLocale == "" {
+		result.defaultLocale = DefaultLocale
+	}
+	texts := map[string]map[string]messages.NotificationTemplate{}
+	for locale, templates := range DefaultTemplates {
+		texts[locale] = map[string]messages.NotificationTemplate{}
+		for name, t := range templates {
+			texts[locale][name] = t
+		}
+	}
+	if config.NotificationTemplatesFile != "" {
+		file, err := os.ReadFile(config.NotificationTemplatesFile)
+		if err != nil {
+			return result, err
+		}
+		overrides := map[string]map[string]messages.NotificationTemplate{}
+		err = json.Unmarshal(file, &overrides)
+		if err != nil {
+			return result, err
+		}
+		for locale, templates := range overrides {
+			if texts[locale] == nil {
+				texts[locale] = map[string]messages.NotificationTemplate{}
+			}
+			for name, t := range templates {
+				texts[locale][name] = t
+			}
+		}
+	}
+	for locale, templates := range texts {
+		result.templates[locale] = map[string]parsedTemplate{}
+		for name, t := range templates {
+			parsed, err := parse(t)
+			if err == nil {
+				err = validateTemplate(parsed)
+			}
+			if err != nil {
+				return result, errors.New("invalid template " + locale + "/" + name + ": " + err.Error())
+			}
+			result.templates[locale][name] = parsed
+		}
+	}
+	if !result.HasLocale(result.defaultLocale) {
+		return result, errors.New("unknown default locale " + result.defaultLocale)
+	}
+	return result, nil
+}
+
+func (this *Templates) HasLocale(locale string) bool {
+	_, ok := this.templates[locale]
+	return ok
+}
+
+// Validate checks the locale and templates of the tenant settings
+func (this *Templates) Validate(settings messages.TenantSettings) error {
+	if settings.Locale != "" && !this.HasLocale(settings.Locale) {
+		return errors.New("unknown locale " + settings.Locale)
+	}
+	for name, t := range settings.Templates {
+		if _, ok := DefaultTemplates[DefaultLocale][name]; !ok {
+			return errors.New("unknown template " + name)
+		}
+		parsed, err := this.parseTenantTemplate(t)
+		if err == nil {
+			err = validateTemplate(parsed)
+		}
+		if err != nil {
+			return errors.New("invalid template " + name + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// Render returns the title and message of the named template; if a template of the tenant can not be rendered, the next template is used
+func (this *Templates) Render(name string, settings messages.TenantSettings, data TemplateData) (title string, message string) {
+	candidates := []parsedTemplate{}
+	if t, ok := settings.Templates[name]; ok {
+		parsed, err := this.parseTenantTemplate(t)
+		if err == nil {
+			candidates = append(candidates, parsed)
+		} else {
+			log.Println("WARNING: unable to parse notification template", name, settings.TenantId, err)
+		}
+	}
+	for _, locale := range []string{settings.Locale, this.defaultLocale, DefaultLocale} {
+		if t, ok := this.templates[locale][name]; ok {
+			candidates = append(candidates, t)
+		}
+	}
+	for _, t := range candidates {
+		title, message, err := render(t, data)
+		if err == nil {
+			return title, message
+		}
+		log.Println("WARNING: unable to render notification template", name, settings.TenantId, err)
+	}
+	return title, message
+}
+
+func (this *Templates) parseTenantTemplate(t messages.NotificationTemplate) (result parsedTemplate, err error) {
+	result.title, err = this.tenantTemplates.parse(t.Title)
+	if err != nil {
+		return result, err
+	}
+	result.message, err = this.tenantTemplates.parse(t.Message)
+	return result, err
+}
+
+func parse(t messages.NotificationTemplate) (result parsedTemplate, err error) {
+	result.title, err = template.New("").Parse(t.Title)
+	if err != nil {
+		return result, err
+	}
+	result.message, err = template.New("").Parse(t.Message)
+	return result, err
+}
+
+func render(t parsedTemplate, data TemplateData) (title string, message string, err error) {
+	title, err = execute(t.title, data)
+	if err != nil {
+		return title, message, err
+	}
+	message, err = execute(t.message, data)
+	return title, message, err
+}
+
+func execute(t *template.Template, data TemplateData) (string, error) {
+	buf := bytes.Buffer{}
+	err := t.Execute(&buf, data)
+	return buf.String(), err
+}
+
+// validateTemplate renders the template with example data, to find unknown fields
+func validateTemplate(t parsedTemplate) error {
+	_, _, err := render(t, TemplateData{
+		Incident:      messages.Incident{Id: "incident", DeploymentName: "process", ErrorMessage: "error"},
+		RestartMode:   messages.OnIncidentRestartModeProcess,
+		RestartNotice: "notice",
+		Error:         "error",
+		MaxRestarts:   1,
+		RestartWindow: "1h0m0s",
+		Digest: messages.NotificationDigest{
+			Count:     2,
+			Interval:  "15m",
+			Processes: []string{"process"},
+			Incidents: []messages.DigestIncident{{Id: "incident", DeploymentName: "process", ErrorMessage: "error"}},
+		},
+	})
+	return err
+}
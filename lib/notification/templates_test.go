@@ -0,0 +1,66 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"testing"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+)
+
+func TestTemplatesRender(t *testing.T) {
+	templates, err := LoadTemplates(configuration.Config{NotificationDefaultLocale: "de"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := TemplateData{Incident: messages.Incident{DeploymentName: "process", ErrorMessage: "error"}}
+
+	title, _ := templates.Render(TemplateIncident, messages.TenantSettings{}, data)
+	if title != "Prozess-Fehler in process" {
+		t.Error(title)
+	}
+	title, _ = templates.Render(TemplateIncident, messages.TenantSettings{Locale: "en"}, data)
+	if title != "Process-Incident in process" {
+		t.Error(title)
+	}
+
+	settings := messages.TenantSettings{Templates: map[string]messages.NotificationTemplate{
+		TemplateIncident: {Title: "{{.Incident.DeploymentName}} failed", Message: "{{.Incident.ErrorMessage}}"},
+	}}
+	for i := 0; i < 2; i++ {
+		title, message := templates.Render(TemplateIncident, settings, data)
+		if title != "process failed" || message != "error" {
+			t.Error(title, message)
+		}
+	}
+	if len(templates.tenantTemplates.templates) != 2 {
+		t.Error("tenant templates should be parsed once", len(templates.tenantTemplates.templates))
+	}
+
+	//a tenant template, that can not be parsed or rendered, falls back to the locale template
+	for _, tenantTemplate := range []messages.NotificationTemplate{{Title: "{{", Message: ""}, {Title: "{{.Unknown}}", Message: ""}} {
+		settings.Templates[TemplateIncident] = tenantTemplate
+		title, _ = templates.Render(TemplateIncident, settings, data)
+		if title != "Prozess-Fehler in process" {
+			t.Error(tenantTemplate, title)
+		}
+		if templates.Validate(settings) == nil {
+			t.Error("expected validation error", tenantTemplate)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader contains the hex encoded hmac-sha256 of the request body, prefixed with "sha256="
+const SignatureHeader = "X-Incident-Signature-256"
+
+// WebhookChannel posts the json encoded ChannelMessage to the url; if a secret is set, the body is signed
+type WebhookChannel struct {
+	Url    string
+	Secret string
+	Client *http.Client
+}
+
+func (this *WebhookChannel) Send(msg ChannelMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if this.Secret != "" {
+		header.Set(SignatureHeader, Sign(this.Secret, body))
+	}
+	return post(this.Client, this.Url, body, header)
+}
+
+// Sign returns the value of the SignatureHeader for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ChatChannel posts the message to a slack/mattermost compatible incoming webhook
+type ChatChannel struct {
+	Url    string
+	Client *http.Client
+}
+
+func (this *ChatChannel) Send(msg ChannelMessage) error {
+	body, err := json.Marshal(map[string]string{"text": "**" + msg.Title + "**\n" + msg.Message.Message})
+	if err != nil {
+		return err
+	}
+	return post(this.Client, this.Url, body, http.Header{})
+}
+
+func post(client *http.Client, url string, body []byte, header http.Header) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	if client == nil {
+		client = guardedClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		respMsg, _ := io.ReadAll(resp.Body)
+		return errors.New("unexpected response status " + resp.Status + ": " + string(respMsg))
+	}
+	return nil
+}
@@ -165,3 +165,132 @@ func checkApiListFilter(t *testing.T, config configuration.Config, query string,
 		return
 	}
 }
+
+func checkApiCount(t *testing.T, config configuration.Config, query string, userToken string, expected int64) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	request, err := http.NewRequest("GET", "http://localhost:"+config.ApiPort+"/incidents/count?"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	request.Header.Add("Authorization", userToken)
+	resp, err := client.Do(request)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		b, _ := io.ReadAll(resp.Body)
+		t.Fatal(resp.StatusCode, string(b))
+		return
+	}
+	var result int64
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if result != expected {
+		t.Fatal(result, expected)
+	}
+}
+
+func checkApiCursorPages(t *testing.T, config configuration.Config, limit string, sort string, userToken string, expected []messages.IncidentMessage) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	result := []messages.IncidentMessage{}
+	cursor := ""
+	for i := 0; i < 100; i++ {
+		query := "limit=" + url.QueryEscape(limit) + "&sort=" + url.QueryEscape(sort)
+		if cursor != "" {
+			query = query + "&cursor=" + url.QueryEscape(cursor)
+		}
+		request, err := http.NewRequest("GET", "http://localhost:"+config.ApiPort+"/incidents?"+query, nil)
+		if err != nil {
+			t.Fatal(err)
+			return
+		}
+		request.Header.Add("Authorization", userToken)
+		resp, err := client.Do(request)
+		if err != nil {
+			t.Fatal(err)
+			return
+		}
+		if resp.StatusCode != 200 {
+			b, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			t.Fatal(resp.StatusCode, string(b))
+			return
+		}
+		page := []messages.IncidentMessage{}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+			return
+		}
+		result = append(result, page...)
+		cursor = resp.Header.Get("X-Next-Cursor")
+		if cursor == "" {
+			break
+		}
+	}
+	if len(expected) != len(result) {
+		t.Fatal(len(expected), len(result), result, expected)
+		return
+	}
+	for i := 0; i < len(result); i++ {
+		if expected[i].Time.Unix() != result[i].Time.Unix() {
+			t.Fatal(expected[i].Time.Unix(), result[i].Time.Unix())
+		}
+		result[i].Time = time.Time{}
+		expected[i].Time = time.Time{}
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatal(result, expected)
+		return
+	}
+}
+
+// checkApiCursorSortMismatch expects that the cursor of a page sorted by firstSort is rejected for a request sorted by secondSort
+func checkApiCursorSortMismatch(t *testing.T, config configuration.Config, firstSort string, secondSort string, userToken string) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	request, err := http.NewRequest("GET", "http://localhost:"+config.ApiPort+"/incidents?limit=1&sort="+url.QueryEscape(firstSort), nil)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	request.Header.Add("Authorization", userToken)
+	resp, err := client.Do(request)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	_, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Fatal(resp.StatusCode)
+		return
+	}
+	cursor := resp.Header.Get("X-Next-Cursor")
+	if cursor == "" {
+		t.Fatal("missing cursor")
+		return
+	}
+	request, err = http.NewRequest("GET", "http://localhost:"+config.ApiPort+"/incidents?limit=1&sort="+url.QueryEscape(secondSort)+"&cursor="+url.QueryEscape(cursor), nil)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	request.Header.Add("Authorization", userToken)
+	resp, err = client.Do(request)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatal(resp.StatusCode, string(b))
+	}
+}
@@ -0,0 +1,357 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib/camundasource"
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/controller"
+	"github.com/SENERGY-Platform/process-incident-api/lib/database"
+	"github.com/SENERGY-Platform/process-incident-api/lib/interfaces"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"github.com/SENERGY-Platform/process-incident-api/lib/metrics"
+	"github.com/SENERGY-Platform/process-incident-api/tests/server/docker"
+)
+
+func TestLeaderElection(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.CamundaIncidentLeaderElection = true
+	config.CamundaIncidentLeaderLeaseDuration = "2s"
+
+	_, ip, err := docker.Mongo(ctx, wg)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.MongoUrl = "mongodb://" + ip + ":27017"
+
+	db, err := database.Factory.Get(ctx, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	first, err := camundasource.NewLeaderElection(ctx, config, db)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	secondCtx, stopSecond := context.WithCancel(ctx)
+	defer stopSecond()
+	second, err := camundasource.NewLeaderElection(secondCtx, config, db)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("only one leader", func(t *testing.T) {
+		if !first.IsLeader() {
+			t.Error("expected first to be leader")
+		}
+		if second.IsLeader() {
+			t.Error("expected second not to be leader")
+		}
+	})
+
+	t.Run("leader renews lease", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			time.Sleep(time.Second)
+			if !first.IsLeader() {
+				t.Error("expected first to stay leader")
+			}
+			if second.IsLeader() {
+				t.Error("expected second not to be leader")
+			}
+		}
+	})
+
+	t.Run("failover after expiration", func(t *testing.T) {
+		//first stops renewing its lease
+		time.Sleep(3 * time.Second)
+		if !second.IsLeader() {
+			t.Error("expected second to take over")
+		}
+	})
+
+	t.Run("failover after shutdown", func(t *testing.T) {
+		third, err := camundasource.NewLeaderElection(ctx, config, db)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if third.IsLeader() {
+			t.Error("expected third not to be leader")
+		}
+		stopSecond()
+		time.Sleep(time.Second)
+		if !third.IsLeader() {
+			t.Error("expected third to take over")
+		}
+	})
+}
+
+func TestLeadershipLostDuringPoll(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.Debug = true
+	config.CamundaIncidentLeaderElection = true
+	config.CamundaIncidentLeaderLeaseDuration = "2s"
+	config.CamundaIncidentRequestInterval = "200ms"
+	config.CamundaIncidentPageSize = 1
+	config.NotificationUrl = ""
+	config.DeveloperNotificationUrl = ""
+
+	_, ip, err := docker.Mongo(ctx, wg)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.MongoUrl = "mongodb://" + ip + ":27017"
+
+	db, err := database.Factory.Get(ctx, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	camunda := &blockingShardCamunda{
+		incidents: []messages.CamundaIncident{
+			{Id: "a", ProcessDefinitionId: "pdid", ProcessInstanceId: "piid_a", TenantId: UserId, IncidentMessage: "error", IncidentTimestamp: time.Now().Format(messages.CamundaTimeFormat)},
+			{Id: "b", ProcessDefinitionId: "pdid", ProcessInstanceId: "piid_b", TenantId: UserId, IncidentMessage: "error", IncidentTimestamp: time.Now().Format(messages.CamundaTimeFormat)},
+			{Id: "c", ProcessDefinitionId: "pdid", ProcessInstanceId: "piid_c", TenantId: UserId, IncidentMessage: "error", IncidentTimestamp: time.Now().Format(messages.CamundaTimeFormat)},
+		},
+		blocked: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	ctrl, err := controller.New(ctx, config, db, camunda, metrics.New())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = camundasource.Start(ctx, config, camunda, db, ctrl, metrics.New())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	countIncidents := func(t *testing.T) int64 {
+		count, err := db.CountIncidents(messages.IncidentFilter{}, UserId)
+		if err != nil {
+			t.Error(err)
+		}
+		return count
+	}
+
+	secondCtx, stopSecond := context.WithCancel(ctx)
+	defer stopSecond()
+
+	t.Run("lease expires while leader loads a page", func(t *testing.T) {
+		select {
+		case <-camunda.blocked:
+		case <-time.After(10 * time.Second):
+			t.Error("expected poll of the leader")
+			return
+		}
+		time.Sleep(3 * time.Second)
+		second, err := camundasource.NewLeaderElection(secondCtx, config, db)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !second.IsLeader() {
+			t.Error("expected second to take over the expired lease")
+		}
+		close(camunda.release)
+		time.Sleep(time.Second)
+		if !second.IsLeader() {
+			t.Error("expected second to stay leader")
+		}
+	})
+
+	t.Run("old leader aborted poll", func(t *testing.T) {
+		if count := countIncidents(t); count != 0 {
+			t.Error(count)
+		}
+	})
+
+	t.Run("old leader polls after failover", func(t *testing.T) {
+		stopSecond()
+		time.Sleep(3 * time.Second)
+		if count := countIncidents(t); count != 3 {
+			t.Error(count)
+		}
+	})
+}
+
+func TestSkipFailingCamundaIncident(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.Debug = true
+	config.CamundaIncidentLeaderElection = false
+	config.CamundaIncidentRequestInterval = "200ms"
+	config.CamundaIncidentMaxAttempts = 2
+	config.NotificationUrl = ""
+	config.DeveloperNotificationUrl = ""
+
+	_, ip, err := docker.Mongo(ctx, wg)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.MongoUrl = "mongodb://" + ip + ":27017"
+
+	db, err := database.Factory.Get(ctx, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	now := time.Now()
+	camunda := &blockingShardCamunda{
+		incidents: []messages.CamundaIncident{
+			{Id: "a", ProcessDefinitionId: "pdid", ProcessInstanceId: "piid_a", TenantId: UserId, IncidentMessage: "error", IncidentTimestamp: now.Add(-3 * time.Second).Format(messages.CamundaTimeFormat)},
+			//missing tenant id; the incident fails in every attempt
+			{Id: "b", ProcessDefinitionId: "pdid", ProcessInstanceId: "piid_b", IncidentMessage: "error", IncidentTimestamp: now.Add(-2 * time.Second).Format(messages.CamundaTimeFormat)},
+			{Id: "c", ProcessDefinitionId: "pdid", ProcessInstanceId: "piid_c", TenantId: UserId, IncidentMessage: "error", IncidentTimestamp: now.Add(-time.Second).Format(messages.CamundaTimeFormat)},
+		},
+		blocked: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	close(camunda.release)
+
+	ctrl, err := controller.New(ctx, config, db, camunda, metrics.New())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = camundasource.Start(ctx, config, camunda, db, ctrl, metrics.New())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	time.Sleep(3 * time.Second)
+
+	t.Run("watermark advanced past failing incident", func(t *testing.T) {
+		watermark, err := db.GetShardWatermark("shard")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expected, err := camunda.incidents[2].Timestamp()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !watermark.Equal(expected) {
+			t.Error(watermark, expected)
+		}
+	})
+
+	t.Run("valid incidents are stored", func(t *testing.T) {
+		count, err := db.CountIncidents(messages.IncidentFilter{}, UserId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if count != 2 {
+			t.Error(count)
+		}
+	})
+
+	t.Run("failure is recorded", func(t *testing.T) {
+		attempts, err := db.AddCamundaIncidentFailure(messages.CamundaIncidentFailure{IncidentId: "b", Shard: "shard"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		//the additional attempt of this check is included
+		if attempts < 3 {
+			t.Error(attempts)
+		}
+	})
+}
+
+// blockingShardCamunda serves incidents of one shard and blocks the first request of the second page until release is closed;
+// methods not needed by the poll are not implemented
+type blockingShardCamunda struct {
+	interfaces.Camunda
+	incidents []messages.CamundaIncident
+	blocked   chan struct{}
+	release   chan struct{}
+	once      sync.Once
+}
+
+func (this *blockingShardCamunda) GetShards() ([]string, error) {
+	return []string{"shard"}, nil
+}
+
+func (this *blockingShardCamunda) GetShardIncidentsAfter(shard string, after time.Time, firstResult int64, maxResults int64) ([]messages.CamundaIncident, error) {
+	if firstResult > 0 {
+		this.once.Do(func() {
+			close(this.blocked)
+			<-this.release
+		})
+	}
+	if firstResult >= int64(len(this.incidents)) {
+		return []messages.CamundaIncident{}, nil
+	}
+	return this.incidents[firstResult:min(firstResult+maxResults, int64(len(this.incidents)))], nil
+}
+
+func (this *blockingShardCamunda) GetProcessName(id string, tenantId string) (string, error) {
+	return "process", nil
+}
+
+func (this *blockingShardCamunda) GetHistoricProcessInstance(id string, userId string) (messages.HistoricProcessInstance, error) {
+	return messages.HistoricProcessInstance{}, nil
+}
+
+func (this *blockingShardCamunda) StopProcessInstance(id string, tenantId string) error {
+	return nil
+}
@@ -21,10 +21,14 @@ import (
 	"github.com/SENERGY-Platform/process-incident-api/lib"
 	"github.com/SENERGY-Platform/process-incident-api/lib/api"
 	"github.com/SENERGY-Platform/process-incident-api/lib/camunda"
+	"github.com/SENERGY-Platform/process-incident-api/lib/client"
 	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
 	"github.com/SENERGY-Platform/process-incident-api/lib/database"
 	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
 	"github.com/SENERGY-Platform/process-incident-api/tests/server"
+	"net/http"
+	"net/url"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -33,6 +37,8 @@ import (
 const UserToken = `Bearer eyJhbGciOiJub25lIn0.eyJzdWIiOiJ1c2VyIiwibmFtZSI6InVzZXIiLCJhZG1pbiI6dHJ1ZSwiaWF0IjoxNzM2MjkyMTI0fQ.`
 const UserId = "user"
 
+const OtherUserToken = `Bearer eyJhbGciOiJub25lIn0.eyJzdWIiOiJvdGhlciIsIm5hbWUiOiJvdGhlciIsImlhdCI6MTczNjI5MjEyNH0.`
+
 func Test(t *testing.T) {
 	wg := &sync.WaitGroup{}
 	defer wg.Wait()
@@ -181,6 +187,58 @@ func Test(t *testing.T) {
 	t.Run("sort", func(t *testing.T) {
 		checkApiLimitAndSort(t, config, "100", "0", "id.desc", UserToken, []messages.IncidentMessage{incident5, incident4, incident3, incident2, incident1})
 	})
+
+	t.Run("cursor", func(t *testing.T) {
+		checkApiCursorPages(t, config, "2", "id", UserToken, []messages.IncidentMessage{incident1, incident2, incident3, incident4, incident5})
+	})
+	t.Run("cursor", func(t *testing.T) {
+		checkApiCursorPages(t, config, "2", "id.desc", UserToken, []messages.IncidentMessage{incident5, incident4, incident3, incident2, incident1})
+	})
+	t.Run("cursor", func(t *testing.T) {
+		checkApiCursorPages(t, config, "3", "process_instance_id", UserToken, []messages.IncidentMessage{incident5, incident1, incident2, incident3, incident4})
+	})
+	t.Run("client cursor", func(t *testing.T) {
+		c := client.New("http://localhost:" + config.ApiPort)
+		ids := []string{}
+		options := messages.FindIncidentsOptions{Limit: 2, SortBy: "id", Asc: false}
+		for i := 0; i < 10; i++ {
+			page, nextCursor, err, _ := c.FindIncidents(UserToken, options)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, incident := range page {
+				ids = append(ids, incident.Id)
+			}
+			if nextCursor == "" {
+				break
+			}
+			options.Cursor = nextCursor
+		}
+		expected := []string{incident5.Id, incident4.Id, incident3.Id, incident2.Id, incident1.Id}
+		if !reflect.DeepEqual(ids, expected) {
+			t.Error(ids, expected)
+		}
+	})
+	t.Run("cursor with other sort direction", func(t *testing.T) {
+		checkApiCursorSortMismatch(t, config, "id.asc", "id.desc", UserToken)
+	})
+	t.Run("cursor with other sort direction", func(t *testing.T) {
+		checkApiCursorSortMismatch(t, config, "time.desc", "time.asc", UserToken)
+	})
+	t.Run("cursor with other sort field", func(t *testing.T) {
+		checkApiCursorSortMismatch(t, config, "id", "process_instance_id", UserToken)
+	})
+
+	t.Run("count", func(t *testing.T) {
+		checkApiCount(t, config, "", UserToken, 5)
+	})
+	t.Run("count by piid", func(t *testing.T) {
+		checkApiCount(t, config, "process_instance_id=piid_1", UserToken, 2)
+	})
+	t.Run("count by task id", func(t *testing.T) {
+		checkApiCount(t, config, "external_task_id=foobar", UserToken, 0)
+	})
 }
 
 func TestTimeSort(t *testing.T) {
@@ -257,4 +315,398 @@ func TestTimeSort(t *testing.T) {
 	t.Run("sort time.desc", func(t *testing.T) {
 		checkApiLimitAndSort(t, config, "100", "0", "time.desc", UserToken, []messages.IncidentMessage{incident2, incident3, incident1})
 	})
+	t.Run("cursor time", func(t *testing.T) {
+		checkApiCursorPages(t, config, "1", "time", UserToken, []messages.IncidentMessage{incident1, incident3, incident2})
+	})
+	t.Run("cursor time.desc", func(t *testing.T) {
+		checkApiCursorPages(t, config, "2", "time.desc", UserToken, []messages.IncidentMessage{incident2, incident3, incident1})
+	})
+}
+
+func TestTimeRange(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	now, err := time.Parse(time.RFC3339, "2020-01-10T12:00:00Z")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	incident1 := messages.IncidentMessage{
+		Id:                  "a",
+		MsgVersion:          3,
+		ExternalTaskId:      "task_id_1",
+		ProcessInstanceId:   "piid_1",
+		ProcessDefinitionId: "pdid_1",
+		WorkerId:            "w",
+		ErrorMessage:        "error message",
+		Time:                now,
+		TenantId:            "user",
+	}
+
+	incident2 := messages.IncidentMessage{
+		Id:                  "b",
+		MsgVersion:          3,
+		ExternalTaskId:      "task_id_1",
+		ProcessInstanceId:   "piid_1",
+		ProcessDefinitionId: "pdid_1",
+		WorkerId:            "w",
+		ErrorMessage:        "error message",
+		Time:                now.Add(3 * time.Hour),
+		TenantId:            "user",
+	}
+
+	incident3 := messages.IncidentMessage{
+		Id:                  "c",
+		MsgVersion:          3,
+		ExternalTaskId:      "task_id_1",
+		ProcessInstanceId:   "piid_1",
+		ProcessDefinitionId: "pdid_1",
+		WorkerId:            "w",
+		ErrorMessage:        "error message",
+		Time:                now.Add(1 * time.Hour),
+		TenantId:            "user",
+	}
+
+	t.Run("create incidents", func(t *testing.T) {
+		createTestIncidents(t, config, []messages.IncidentMessage{incident1, incident2, incident3})
+	})
+	t.Run("from", func(t *testing.T) {
+		checkApiListFilter(t, config, "from="+url.QueryEscape("2020-01-10T13:00:00Z"), UserToken, []messages.IncidentMessage{incident2, incident3})
+	})
+	t.Run("until", func(t *testing.T) {
+		checkApiListFilter(t, config, "until="+url.QueryEscape("2020-01-10T13:00:00Z"), UserToken, []messages.IncidentMessage{incident1, incident3})
+	})
+	t.Run("from until", func(t *testing.T) {
+		checkApiListFilter(t, config, "from="+url.QueryEscape("2020-01-10T12:30:00Z")+"&until="+url.QueryEscape("2020-01-10T14:00:00Z"), UserToken, []messages.IncidentMessage{incident3})
+	})
+	t.Run("empty range", func(t *testing.T) {
+		checkApiListFilter(t, config, "from="+url.QueryEscape("2021-01-01T00:00:00Z"), UserToken, []messages.IncidentMessage{})
+	})
+}
+
+func TestSearch(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	incident1 := messages.IncidentMessage{
+		Id:                  "a",
+		MsgVersion:          3,
+		ExternalTaskId:      "task_id_1",
+		ProcessInstanceId:   "piid_1",
+		ProcessDefinitionId: "pdid_1",
+		WorkerId:            "w",
+		ErrorMessage:        "timeout while waiting for device urn:device:1234",
+		Time:                time.Now(),
+		TenantId:            "user",
+		DeploymentName:      "lamp control",
+		BusinessKey:         "bk1",
+	}
+
+	incident2 := messages.IncidentMessage{
+		Id:                  "b",
+		MsgVersion:          3,
+		ExternalTaskId:      "task_id_2",
+		ProcessInstanceId:   "piid_2",
+		ProcessDefinitionId: "pdid_2",
+		WorkerId:            "w",
+		ErrorMessage:        "unknown service",
+		Time:                time.Now(),
+		TenantId:            "user",
+		DeploymentName:      "heating",
+		BusinessKey:         "bk2",
+	}
+
+	incident3 := messages.IncidentMessage{
+		Id:                  "c",
+		MsgVersion:          3,
+		ExternalTaskId:      "task_id_3",
+		ProcessInstanceId:   "piid_3",
+		ProcessDefinitionId: "pdid_3",
+		WorkerId:            "w",
+		ErrorMessage:        "timeout",
+		Time:                time.Now(),
+		TenantId:            "other",
+		DeploymentName:      "heating",
+		BusinessKey:         "bk3",
+	}
+
+	t.Run("create incidents", func(t *testing.T) {
+		createTestIncidents(t, config, []messages.IncidentMessage{incident1, incident2, incident3})
+	})
+	t.Run("search error message", func(t *testing.T) {
+		checkApiListFilter(t, config, "search=timeout", UserToken, []messages.IncidentMessage{incident1})
+	})
+	t.Run("search device id", func(t *testing.T) {
+		checkApiListFilter(t, config, "search="+url.QueryEscape("urn:device:1234"), UserToken, []messages.IncidentMessage{incident1})
+	})
+	t.Run("search deployment name", func(t *testing.T) {
+		checkApiListFilter(t, config, "search=heating", UserToken, []messages.IncidentMessage{incident2})
+	})
+	t.Run("search business key", func(t *testing.T) {
+		checkApiListFilter(t, config, "search=bk2", UserToken, []messages.IncidentMessage{incident2})
+	})
+	t.Run("search unknown", func(t *testing.T) {
+		checkApiListFilter(t, config, "search=foobar", UserToken, []messages.IncidentMessage{})
+	})
+	t.Run("count search", func(t *testing.T) {
+		checkApiCount(t, config, "search=timeout", UserToken, 1)
+	})
+}
+
+func TestIncidentStatus(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	incident1 := messages.IncidentMessage{
+		Id:                  "a",
+		MsgVersion:          3,
+		ExternalTaskId:      "task_id_1",
+		ProcessInstanceId:   "piid_1",
+		ProcessDefinitionId: "pdid_1",
+		WorkerId:            "w",
+		ErrorMessage:        "error message",
+		Time:                time.Now(),
+		TenantId:            "user",
+	}
+
+	incident2 := messages.IncidentMessage{
+		Id:                  "b",
+		MsgVersion:          3,
+		ExternalTaskId:      "task_id_2",
+		ProcessInstanceId:   "piid_2",
+		ProcessDefinitionId: "pdid_2",
+		WorkerId:            "w",
+		ErrorMessage:        "error message",
+		Time:                time.Now(),
+		TenantId:            "user",
+	}
+
+	incident3 := messages.IncidentMessage{
+		Id:                  "c",
+		MsgVersion:          3,
+		ExternalTaskId:      "task_id_3",
+		ProcessInstanceId:   "piid_3",
+		ProcessDefinitionId: "pdid_3",
+		WorkerId:            "w",
+		ErrorMessage:        "error message",
+		Time:                time.Now(),
+		TenantId:            "other",
+	}
+
+	c := client.New("http://localhost:" + config.ApiPort)
+
+	t.Run("create incidents", func(t *testing.T) {
+		createTestIncidents(t, config, []messages.IncidentMessage{incident1, incident2, incident3})
+	})
+
+	t.Run("initially open", func(t *testing.T) {
+		checkApiCount(t, config, "status=open", UserToken, 2)
+	})
+
+	t.Run("acknowledge", func(t *testing.T) {
+		err, _ := c.SetIncidentStatus(UserToken, "a", messages.IncidentStatusAcknowledged)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		incident, err, _ := c.GetIncident(UserToken, "a")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if incident.Status != messages.IncidentStatusAcknowledged || incident.StatusChangedBy != UserId || incident.StatusChangedAt.IsZero() {
+			t.Errorf("%#v", incident)
+		}
+		checkApiCount(t, config, "status=open", UserToken, 1)
+		checkApiCount(t, config, "status=acknowledged", UserToken, 1)
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		err, _ := c.SetIncidentStatus(UserToken, "b", messages.IncidentStatusResolved)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		checkApiCount(t, config, "status=open", UserToken, 0)
+		checkApiCount(t, config, "status=resolved", UserToken, 1)
+	})
+
+	t.Run("reopen", func(t *testing.T) {
+		err, _ := c.SetIncidentStatus(UserToken, "b", messages.IncidentStatusOpen)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		checkApiCount(t, config, "status=open", UserToken, 1)
+		checkApiCount(t, config, "status=resolved", UserToken, 0)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		err, code := c.SetIncidentStatus(UserToken, "a", "foo")
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+		_, err, code = c.CountIncidents(UserToken, messages.IncidentFilter{Status: "foo"})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+		_, _, err, code = c.FindIncidents(UserToken, messages.FindIncidentsOptions{IncidentFilter: messages.IncidentFilter{Status: "foo"}, Limit: 10, SortBy: "id", Asc: true})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+	})
+
+	t.Run("other tenant", func(t *testing.T) {
+		err, code := c.SetIncidentStatus(UserToken, "c", messages.IncidentStatusResolved)
+		if err == nil || code != http.StatusNotFound {
+			t.Error(err, code)
+		}
+	})
+}
+
+func TestIncidentComments(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	incident := messages.IncidentMessage{
+		Id:                  "a",
+		MsgVersion:          3,
+		ExternalTaskId:      "task_id_1",
+		ProcessInstanceId:   "piid_1",
+		ProcessDefinitionId: "pdid_1",
+		WorkerId:            "w",
+		ErrorMessage:        "error message",
+		Time:                time.Now(),
+		TenantId:            "user",
+	}
+
+	c := client.New("http://localhost:" + config.ApiPort)
+
+	t.Run("create incidents", func(t *testing.T) {
+		createTestIncidents(t, config, []messages.IncidentMessage{incident})
+	})
+
+	t.Run("add comments", func(t *testing.T) {
+		for _, text := range []string{"first", "second"} {
+			comment, err, _ := c.AddIncidentComment(UserToken, "a", messages.IncidentComment{Text: text})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if comment.Id == "" || comment.UserId != UserId || comment.IncidentId != "a" || comment.Text != text {
+				t.Errorf("%#v", comment)
+			}
+		}
+	})
+
+	t.Run("add empty comment", func(t *testing.T) {
+		_, err, code := c.AddIncidentComment(UserToken, "a", messages.IncidentComment{})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+	})
+
+	t.Run("list comments", func(t *testing.T) {
+		comments, err, _ := c.ListIncidentComments(UserToken, "a")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(comments) != 2 || comments[0].Text != "first" || comments[1].Text != "second" {
+			t.Errorf("%#v", comments)
+		}
+	})
+
+	t.Run("other tenant", func(t *testing.T) {
+		_, err, code := c.ListIncidentComments(OtherUserToken, "a")
+		if err == nil || code != http.StatusNotFound {
+			t.Error(err, code)
+		}
+		_, err, code = c.AddIncidentComment(OtherUserToken, "a", messages.IncidentComment{Text: "foo"})
+		if err == nil || code != http.StatusNotFound {
+			t.Error(err, code)
+		}
+	})
 }
@@ -0,0 +1,860 @@
+/*
+ * Copyright 2025 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-incident-api/lib"
+	"github.com/SENERGY-Platform/process-incident-api/lib/api"
+	"github.com/SENERGY-Platform/process-incident-api/lib/camunda"
+	"github.com/SENERGY-Platform/process-incident-api/lib/client"
+	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/controller"
+	"github.com/SENERGY-Platform/process-incident-api/lib/database"
+	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"github.com/SENERGY-Platform/process-incident-api/lib/metrics"
+	"github.com/SENERGY-Platform/process-incident-api/lib/notification"
+	"github.com/SENERGY-Platform/process-incident-api/tests/server"
+	"github.com/SENERGY-Platform/process-incident-api/tests/server/docker"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestNotificationOutbox(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.NotificationMaxAttempts = 3
+	config.NotificationRetryBaseDelay = "100ms"
+	config.NotificationRetryMaxDelay = "1s"
+
+	_, ip, err := docker.Mongo(ctx, wg)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.MongoUrl = "mongodb://" + ip + ":27017"
+
+	mux := sync.Mutex{}
+	available := false
+	received := []string{}
+	notifier := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mux.Lock()
+		defer mux.Unlock()
+		if !available {
+			http.Error(writer, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		received = append(received, request.URL.Path)
+	}))
+	defer notifier.Close()
+	config.NotificationUrl = notifier.URL
+
+	db, err := database.Factory.Get(ctx, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	dispatcher, err := notification.NewDispatcher(config, db, metrics.New())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	getEntry := func(t *testing.T, id string) (entry messages.OutboxEntry, found bool) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoUrl))
+		if err != nil {
+			t.Error(err)
+			return entry, false
+		}
+		err = client.Database(config.MongoDatabaseName).Collection(config.MongoOutboxCollectionName).FindOne(ctx, bson.M{"id": id}).Decode(&entry)
+		if err == mongo.ErrNoDocuments {
+			return entry, false
+		}
+		if err != nil {
+			t.Error(err)
+		}
+		return entry, true
+	}
+
+	enqueue := func(t *testing.T, id string) {
+		now := time.Now()
+		err := db.EnqueueNotification(messages.OutboxEntry{
+			Id:          id,
+			UserId:      "user",
+			Title:       "title",
+			Message:     "message",
+			Topic:       notification.Topic,
+			Status:      messages.OutboxStatusPending,
+			NextAttempt: now,
+			Created:     now,
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	t.Run("retry while notifier is unavailable", func(t *testing.T) {
+		enqueue(t, "retry")
+		dispatcher.Dispatch()
+		entry, found := getEntry(t, "retry")
+		if !found {
+			t.Error("expected entry to stay in outbox")
+			return
+		}
+		if entry.Status != messages.OutboxStatusPending || entry.Attempts != 1 || entry.LastError == "" || !entry.NextAttempt.After(time.Now()) {
+			t.Errorf("%#v", entry)
+		}
+	})
+
+	t.Run("entry is not attempted before backoff", func(t *testing.T) {
+		dispatcher.Dispatch()
+		entry, _ := getEntry(t, "retry")
+		if entry.Attempts != 1 {
+			t.Errorf("%#v", entry)
+		}
+	})
+
+	t.Run("deliver after notifier recovered", func(t *testing.T) {
+		time.Sleep(200 * time.Millisecond)
+		mux.Lock()
+		available = true
+		mux.Unlock()
+		dispatcher.Dispatch()
+		_, found := getEntry(t, "retry")
+		if found {
+			t.Error("expected delivered entry to be removed")
+		}
+		mux.Lock()
+		defer mux.Unlock()
+		if len(received) != 1 || received[0] != "/notifications" {
+			t.Error(received)
+		}
+	})
+
+	t.Run("dead letter", func(t *testing.T) {
+		mux.Lock()
+		available = false
+		mux.Unlock()
+		enqueue(t, "dead")
+		for i := 0; i < 3; i++ {
+			dispatcher.Dispatch()
+			time.Sleep(time.Second)
+		}
+		entry, found := getEntry(t, "dead")
+		if !found {
+			t.Error("expected dead-lettered entry to stay in outbox")
+			return
+		}
+		if entry.Status != messages.OutboxStatusDeadLetter || entry.Attempts != 3 || !entry.ExpiresAt.After(time.Now()) {
+			t.Errorf("%#v", entry)
+		}
+		mux.Lock()
+		available = true
+		mux.Unlock()
+		dispatcher.Dispatch()
+		mux.Lock()
+		defer mux.Unlock()
+		if len(received) != 1 {
+			t.Error("dead-lettered entries should not be delivered", received)
+		}
+	})
+}
+
+func TestNotificationChannels(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+	defaultConfig.NotificationDispatchInterval = "1s"
+	defaultConfig.NotificationChannelAllowedHosts = []string{"127.0.0.1"}
+	defaultConfig.NotificationChannelDeniedHosts = []string{"*.denied.example.com"}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mux := sync.Mutex{}
+	webhookMessages := []notification.ChannelMessage{}
+	chatMessages := []map[string]string{}
+
+	webhook := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		if request.Header.Get(notification.SignatureHeader) != notification.Sign("secret", body) {
+			t.Error("invalid signature", request.Header.Get(notification.SignatureHeader))
+		}
+		msg := notification.ChannelMessage{}
+		err := json.Unmarshal(body, &msg)
+		if err != nil {
+			t.Error(err)
+		}
+		mux.Lock()
+		defer mux.Unlock()
+		webhookMessages = append(webhookMessages, msg)
+	}))
+	defer webhook.Close()
+
+	chat := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		msg := map[string]string{}
+		err := json.NewDecoder(request.Body).Decode(&msg)
+		if err != nil {
+			t.Error(err)
+		}
+		mux.Lock()
+		defer mux.Unlock()
+		chatMessages = append(chatMessages, msg)
+	}))
+	defer chat.Close()
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	c := client.New("http://localhost:" + config.ApiPort)
+
+	webhookChannelId := ""
+
+	t.Run("set channels", func(t *testing.T) {
+		_, err, code := c.SetNotificationChannel(UserToken, messages.NotificationChannel{Type: messages.NotificationChannelTypeWebhook, Url: "foo"})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+		for _, u := range []string{"http://169.254.169.254/latest/meta-data", "http://localhost:8080/hook", "http://10.0.0.1/hook", "http://[::1]/hook", "https://hooks.denied.example.com/hook"} {
+			_, err, code = c.SetNotificationChannel(UserToken, messages.NotificationChannel{Type: messages.NotificationChannelTypeWebhook, Url: u})
+			if err == nil || code != http.StatusBadRequest {
+				t.Error(u, err, code)
+			}
+		}
+		_, err, code = c.SetNotificationChannel(UserToken, messages.NotificationChannel{Type: messages.NotificationChannelTypeEmail, Recipients: []string{"foo@example.com"}})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error("email channels should not be available without smtp config", err, code)
+		}
+		result, err, _ := c.SetNotificationChannel(UserToken, messages.NotificationChannel{
+			TenantId: UserId,
+			Type:     messages.NotificationChannelTypeWebhook,
+			Url:      webhook.URL,
+			Secret:   "secret",
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Id == "" || result.Secret != "" {
+			t.Errorf("%#v", result)
+		}
+		webhookChannelId = result.Id
+		_, err, _ = c.SetNotificationChannel(UserToken, messages.NotificationChannel{
+			TenantId:            UserId,
+			ProcessDefinitionId: "pdid1",
+			Type:                messages.NotificationChannelTypeChat,
+			Url:                 chat.URL,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("check channel access", func(t *testing.T) {
+		channel, err, _ := c.GetNotificationChannel(UserToken, webhookChannelId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if channel.Secret != "" || channel.Url != webhook.URL {
+			t.Errorf("%#v", channel)
+		}
+		_, err, code := c.GetNotificationChannel(OtherUserToken, webhookChannelId)
+		if err == nil || code != http.StatusForbidden {
+			t.Error(err, code)
+		}
+		channels, err, _ := c.ListNotificationChannels(OtherUserToken, 100, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(channels) != 0 {
+			t.Errorf("%#v", channels)
+		}
+	})
+
+	t.Run("send incidents", func(t *testing.T) {
+		for _, incident := range []messages.Incident{
+			{Id: "a", ProcessDefinitionId: "pdid1", DeploymentName: "d1"},
+			{Id: "b", ProcessDefinitionId: "pdid2", DeploymentName: "d2"},
+		} {
+			incident.MsgVersion = 3
+			incident.ProcessInstanceId = "piid1"
+			incident.ErrorMessage = "error message"
+			incident.Time = time.Now()
+			incident.TenantId = UserId
+			err, _ = c.CreateIncident(client.InternalAdminToken, incident)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	})
+
+	time.Sleep(5 * time.Second)
+
+	t.Run("check channel notifications", func(t *testing.T) {
+		mux.Lock()
+		defer mux.Unlock()
+		if len(webhookMessages) != 2 {
+			t.Errorf("%#v", webhookMessages)
+			return
+		}
+		for _, msg := range webhookMessages {
+			if msg.UserId != UserId || msg.IncidentId == "" || msg.ProcessInstanceId != "piid1" || msg.Title == "" {
+				t.Errorf("%#v", msg)
+			}
+		}
+		if len(chatMessages) != 1 || !strings.Contains(chatMessages[0]["text"], "d1") {
+			t.Errorf("%#v", chatMessages)
+		}
+	})
+
+	t.Run("delete channel", func(t *testing.T) {
+		err, code := c.DeleteNotificationChannel(OtherUserToken, webhookChannelId)
+		if err == nil || code != http.StatusForbidden {
+			t.Error(err, code)
+		}
+		err, _ = c.DeleteNotificationChannel(UserToken, webhookChannelId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err, code = c.GetNotificationChannel(UserToken, webhookChannelId)
+		if err == nil || code != http.StatusNotFound {
+			t.Error(err, code)
+		}
+	})
+}
+
+func TestNotificationTemplates(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, getReceived, err := startNotificationService(ctx, wg, t, func(config *configuration.Config) {
+		config.NotificationDispatchInterval = "1s"
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	sendIncident := func(t *testing.T, id string) {
+		err, _ := c.CreateIncident(client.InternalAdminToken, messages.Incident{
+			Id:                  id,
+			MsgVersion:          3,
+			ProcessDefinitionId: "pdid-" + id,
+			ProcessInstanceId:   "piid-" + id,
+			DeploymentName:      "deployment",
+			ErrorMessage:        "error message",
+			Time:                time.Now(),
+			TenantId:            UserId,
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	getLastMessage := func(t *testing.T) notification.Message {
+		time.Sleep(3 * time.Second)
+		received := getReceived()
+		if len(received) == 0 {
+			t.Error("expected notification")
+			return notification.Message{}
+		}
+		return received[len(received)-1]
+	}
+
+	t.Run("default locale", func(t *testing.T) {
+		sendIncident(t, "a")
+		msg := getLastMessage(t)
+		if msg.Title != "Process-Incident in deployment" || msg.Message != "error message" {
+			t.Errorf("%#v", msg)
+		}
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		err, code := c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, Locale: "xx"})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+		err, code = c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, Templates: map[string]messages.NotificationTemplate{
+			notification.TemplateIncident: {Title: "{{.Unknown}}"},
+		}})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+		err, code = c.SetTenantSettings(OtherUserToken, messages.TenantSettings{TenantId: UserId, Locale: "de"})
+		if err == nil || code != http.StatusForbidden {
+			t.Error(err, code)
+		}
+	})
+
+	t.Run("german locale", func(t *testing.T) {
+		err, _ := c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, Locale: "de"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sendIncident(t, "b")
+		msg := getLastMessage(t)
+		if msg.Title != "Prozess-Fehler in deployment" || msg.Message != "error message" {
+			t.Errorf("%#v", msg)
+		}
+	})
+
+	t.Run("tenant template", func(t *testing.T) {
+		err, _ := c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, Locale: "de", Templates: map[string]messages.NotificationTemplate{
+			notification.TemplateIncident: {Title: "{{.Incident.DeploymentName}} failed", Message: "{{.Incident.ProcessInstanceId}}: {{.Incident.ErrorMessage}}"},
+		}})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		settings, err, _ := c.GetTenantSettings(UserToken, UserId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if settings.Locale != "de" || len(settings.Templates) != 1 {
+			t.Errorf("%#v", settings)
+		}
+		sendIncident(t, "c")
+		msg := getLastMessage(t)
+		if msg.Title != "deployment failed" || msg.Message != "piid-c: error message" {
+			t.Errorf("%#v", msg)
+		}
+	})
+
+	t.Run("delete settings", func(t *testing.T) {
+		err, _ := c.DeleteTenantSettings(UserToken, UserId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sendIncident(t, "d")
+		msg := getLastMessage(t)
+		if msg.Title != "Process-Incident in deployment" {
+			t.Errorf("%#v", msg)
+		}
+	})
+}
+
+func TestNotificationDigest(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, getReceived, err := startNotificationService(ctx, wg, t, func(config *configuration.Config) {
+		config.NotificationDispatchInterval = "1s"
+		config.NotificationDigestInterval = "5s"
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	sendIncident := func(t *testing.T, id string, processDefinitionId string) {
+		err, _ := c.CreateIncident(client.InternalAdminToken, messages.Incident{
+			Id:                  id,
+			MsgVersion:          3,
+			ProcessDefinitionId: processDefinitionId,
+			ProcessInstanceId:   "piid-" + id,
+			DeploymentName:      "deployment-" + processDefinitionId,
+			ErrorMessage:        "error message",
+			Time:                time.Now(),
+			TenantId:            UserId,
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	t.Run("invalid settings", func(t *testing.T) {
+		err, code := c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, DigestInterval: "foo"})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+		err, code = c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, RateLimit: -2})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		err, _ := c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, RateLimit: 2, RateLimitWindow: "1h"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sendIncident(t, "a", "pd1")
+		sendIncident(t, "b", "pd2")
+		sendIncident(t, "c", "pd1")
+		sendIncident(t, "d", "pd2")
+		sendIncident(t, "e", "pd3")
+		time.Sleep(3 * time.Second)
+		if result := getReceived(); len(result) != 2 {
+			t.Errorf("%#v", result)
+		}
+	})
+
+	t.Run("rate limit digest", func(t *testing.T) {
+		time.Sleep(5 * time.Second)
+		result := getReceived()
+		if len(result) != 3 {
+			t.Errorf("%#v", result)
+			return
+		}
+		digest := result[2]
+		if digest.Title != "3 Process-Incidents in 3 processes" || !strings.Contains(digest.Message, "deployment-pd3: error message") {
+			t.Errorf("%#v", digest)
+		}
+	})
+
+	t.Run("handler digest", func(t *testing.T) {
+		err, _ := c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, RateLimit: -1})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err, _ = c.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: "pd4",
+			Notify:              true,
+			Digest:              true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sendIncident(t, "f", "pd4")
+		sendIncident(t, "g", "pd5")
+		time.Sleep(3 * time.Second)
+		result := getReceived()
+		if len(result) != 4 || result[3].Title != "Process-Incident in deployment-pd5" {
+			t.Errorf("%#v", result)
+			return
+		}
+		time.Sleep(5 * time.Second)
+		result = getReceived()
+		if len(result) != 5 || result[4].Title != "1 Process-Incident in 1 process" {
+			t.Errorf("%#v", result)
+		}
+	})
+}
+
+func TestNotificationRateLimitWithoutTenantSettings(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, getReceived, err := startNotificationService(ctx, wg, t, func(config *configuration.Config) {
+		config.NotificationDispatchInterval = "1s"
+		config.NotificationDigestInterval = "1h"
+		config.NotificationRateLimit = 2
+		config.NotificationRateLimitWindow = "1h"
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("send incidents", func(t *testing.T) {
+		for _, incident := range []messages.Incident{
+			{Id: "a", TenantId: "limited-tenant"},
+			{Id: "b", TenantId: "limited-tenant"},
+			{Id: "c", TenantId: "limited-tenant"},
+			{Id: "d", TenantId: "other-tenant"},
+		} {
+			incident.MsgVersion = 3
+			incident.ProcessDefinitionId = "pd-" + incident.Id
+			incident.ProcessInstanceId = "piid-" + incident.Id
+			incident.ErrorMessage = "error message"
+			incident.Time = time.Now()
+			err, _ := c.CreateIncident(client.InternalAdminToken, incident)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	})
+
+	t.Run("check rate limit per tenant", func(t *testing.T) {
+		time.Sleep(3 * time.Second)
+		received := getReceived()
+		count := map[string]int{}
+		for _, msg := range received {
+			count[msg.UserId] = count[msg.UserId] + 1
+		}
+		if count["limited-tenant"] != 2 || count["other-tenant"] != 1 {
+			t.Errorf("%#v", received)
+		}
+	})
+}
+
+func TestNotificationQuietHours(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, getReceived, err := startNotificationService(ctx, wg, t, func(config *configuration.Config) {
+		config.NotificationDispatchInterval = "1s"
+		config.NotificationDigestInterval = "5s"
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	sendIncident := func(t *testing.T, id string, processDefinitionId string, errorMessage string) {
+		err, _ := c.CreateIncident(client.InternalAdminToken, messages.Incident{
+			Id:                  id,
+			MsgVersion:          3,
+			ProcessDefinitionId: processDefinitionId,
+			ProcessInstanceId:   "piid-" + id,
+			DeploymentName:      "deployment-" + processDefinitionId,
+			ErrorMessage:        errorMessage,
+			Time:                time.Now(),
+			TenantId:            UserId,
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	now := time.Now().UTC()
+	quietHours := &messages.QuietHours{
+		Start:    now.Add(-time.Hour).Format("15:04"),
+		End:      now.Add(time.Hour).Format("15:04"),
+		TimeZone: "UTC",
+		Routing: map[string]string{
+			messages.SeverityLow:    messages.QuietHoursActionSuppress,
+			messages.SeverityMedium: messages.QuietHoursActionDelay,
+		},
+	}
+
+	t.Run("invalid settings", func(t *testing.T) {
+		err, code := c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, SeverityRules: []messages.SeverityRule{{Severity: "foo"}}})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+		err, code = c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, SeverityRules: []messages.SeverityRule{{ErrorMessagePattern: "(", Severity: messages.SeverityLow}}})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+		err, code = c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, QuietHours: &messages.QuietHours{Start: "22", End: "07:00", TimeZone: "UTC"}})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+		err, code = c.SetTenantSettings(UserToken, messages.TenantSettings{TenantId: UserId, QuietHours: &messages.QuietHours{Start: "22:00", End: "07:00", TimeZone: "UTC", Routing: map[string]string{messages.SeverityLow: "foo"}}})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+	})
+
+	t.Run("set settings", func(t *testing.T) {
+		err, _ := c.SetTenantSettings(UserToken, messages.TenantSettings{
+			TenantId: UserId,
+			SeverityRules: []messages.SeverityRule{
+				{ErrorMessagePattern: "^fatal", Severity: messages.SeverityCritical},
+				{ProcessDefinitionId: "pd-low", Severity: messages.SeverityLow},
+			},
+			QuietHours: quietHours,
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("send incidents", func(t *testing.T) {
+		sendIncident(t, "low", "pd-low", "error message")
+		sendIncident(t, "medium", "pd-medium", "error message")
+		sendIncident(t, "critical", "pd-low", "fatal error message")
+		time.Sleep(3 * time.Second)
+	})
+
+	t.Run("check severity", func(t *testing.T) {
+		expected := map[string]string{
+			"low":      messages.SeverityLow,
+			"medium":   messages.SeverityMedium,
+			"critical": messages.SeverityCritical,
+		}
+		for id, severity := range expected {
+			incident, err, _ := c.GetIncident(UserToken, id)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if incident.Severity != severity {
+				t.Error(id, incident.Severity, severity)
+			}
+		}
+	})
+
+	t.Run("check notifications", func(t *testing.T) {
+		result := getReceived()
+		if len(result) != 1 {
+			t.Errorf("%#v", result)
+			return
+		}
+		if !strings.Contains(result[0].Message, "fatal error message") {
+			t.Errorf("%#v", result[0])
+		}
+	})
+
+	t.Run("delayed incident postpones existing digest", func(t *testing.T) {
+		err, _ := c.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: "pd-digest",
+			Notify:              true,
+			Digest:              true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		sendIncident(t, "digest-critical", "pd-digest", "fatal error message")
+		sendIncident(t, "digest-medium", "pd-digest", "error message")
+		time.Sleep(8 * time.Second)
+		if result := getReceived(); len(result) != 1 {
+			t.Errorf("digest should be sent after the quiet hours %#v", result)
+		}
+	})
+
+	t.Run("restart notifications follow quiet hours", func(t *testing.T) {
+		err, _ := c.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: "pd-low",
+			Restart:             true,
+			Notify:              false,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		//pd-low is not deployed, so both restarts fail; only the failure of the critical incident is notified
+		sendIncident(t, "restart-low", "pd-low", "error message")
+		sendIncident(t, "restart-critical", "pd-low", "fatal error message")
+		time.Sleep(3 * time.Second)
+		result := getReceived()
+		if len(result) != 2 {
+			t.Errorf("%#v", result)
+			return
+		}
+		if !strings.Contains(result[1].Title, "unable to restart") || !strings.Contains(result[1].Message, "fatal error message") {
+			t.Errorf("%#v", result[1])
+		}
+	})
+}
+
+func TestInvalidDefaultSeverity(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.IncidentDefaultSeverity = "foo"
+	_, err = controller.New(ctx, config, nil, nil, metrics.New())
+	if err == nil {
+		t.Error("expected error for unknown default severity")
+	}
+}
+
+// startNotificationService starts the service with the default config, changed by configure.
+// notifications are delivered to a test notifier; getReceived returns the messages it received so far
+func startNotificationService(ctx context.Context, wg *sync.WaitGroup, t *testing.T, configure func(config *configuration.Config)) (c *client.ClientImpl, getReceived func() []notification.Message, err error) {
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		return c, getReceived, err
+	}
+	defaultConfig.Debug = true
+	configure(&defaultConfig)
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		return c, getReceived, err
+	}
+
+	mux := sync.Mutex{}
+	received := []notification.Message{}
+	notifier := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		msg := notification.Message{}
+		err := json.NewDecoder(request.Body).Decode(&msg)
+		if err != nil {
+			t.Error(err)
+		}
+		mux.Lock()
+		defer mux.Unlock()
+		received = append(received, msg)
+	}))
+	t.Cleanup(notifier.Close)
+	config.NotificationUrl = notifier.URL
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		return c, getReceived, err
+	}
+
+	c = client.New("http://localhost:" + config.ApiPort)
+	getReceived = func() []notification.Message {
+		mux.Lock()
+		defer mux.Unlock()
+		return append([]notification.Message{}, received...)
+	}
+	return c, getReceived, nil
+}
@@ -2,9 +2,12 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/SENERGY-Platform/process-incident-api/lib"
 	"github.com/SENERGY-Platform/process-incident-api/lib/api"
 	"github.com/SENERGY-Platform/process-incident-api/lib/camunda"
+	"github.com/SENERGY-Platform/process-incident-api/lib/camunda/cache"
+	"github.com/SENERGY-Platform/process-incident-api/lib/camunda/shards"
 	"github.com/SENERGY-Platform/process-incident-api/lib/client"
 	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
 	"github.com/SENERGY-Platform/process-incident-api/lib/controller"
@@ -21,6 +24,8 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -153,6 +158,15 @@ func TestScriptIncident(t *testing.T) {
 				t.Errorf("%#v", incident)
 				return
 			}
+			if incident.IncidentType != "failedJob" || incident.ActivityId == "" || incident.Configuration == "" {
+				t.Errorf("missing camunda metadata %#v", incident)
+			}
+			if incident.StackTrace == "" || int64(len(incident.StackTrace)) > config.IncidentStackTraceMaxLength {
+				t.Errorf("unexpected stack trace length %v", len(incident.StackTrace))
+			}
+			if incident.Time.IsZero() || incident.Time.After(time.Now()) {
+				t.Errorf("unexpected incident time %#v", incident)
+			}
 			counter = counter + 1
 			if duplicateInstance[incident.ProcessInstanceId] {
 				t.Error("duplicate process instance found")
@@ -173,4 +187,975 @@ func TestScriptIncident(t *testing.T) {
 			t.Error("expected at least two incidents")
 		}
 	})
+
+	t.Run("check watermark", func(t *testing.T) {
+		s, err := shards.New(config.ShardsDb, cache.None)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		shard, err := s.EnsureShardForUser("testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		db, err := database.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		watermark, err := db.GetShardWatermark(shard)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		t.Log("log: watermark =", watermark)
+		if watermark.IsZero() {
+			t.Error("expected stored watermark")
+		}
+	})
+}
+
+func TestScriptIncidentWithStartParameters(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	defaultConfig.MetricsPort, err = docker.GetFreePortStr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	notificationTestServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		msg, _ := io.ReadAll(request.Body)
+		t.Log("notification:", request.URL.String(), string(msg))
+	}))
+	defer notificationTestServer.Close()
+	config.NotificationUrl = notificationTestServer.URL
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	processId := ""
+
+	t.Run("deploy process", func(t *testing.T) {
+		processId, err = deployProcessWithInfo(config, "test", resources.ScriptErrParamsBpmn, resources.SvgExample, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("set incident handler", func(t *testing.T) {
+		err, _ = client.New("http://localhost:"+config.ApiPort).SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId:        processId,
+			Restart:                    true,
+			RestartWithStartParameters: true,
+			Notify:                     true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("start process", func(t *testing.T) {
+		c, err := camunda.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = c.StartProcessWithVariables(processId, "", map[string]messages.CamundaVariable{"foo": {Value: "bar", Type: "String"}}, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	time.Sleep(1 * time.Minute)
+
+	t.Run("check restarted instances", func(t *testing.T) {
+		s, err := shards.New(config.ShardsDb, cache.None)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		shard, err := s.EnsureShardForUser("testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp, err := http.Get(shard + "/engine-rest/history/variable-instance?variableName=foo")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		variables := []camunda.HistoricVariableInstance{}
+		err = json.NewDecoder(resp.Body).Decode(&variables)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		t.Log("log: variable count =", len(variables))
+		if len(variables) < 2 {
+			t.Error("expected at least one restarted instance with start parameters")
+		}
+		for _, variable := range variables {
+			if variable.Value != "bar" {
+				t.Error(variable)
+			}
+		}
+	})
+}
+
+func TestScriptIncidentWithChangedStartParameters(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	defaultConfig.MetricsPort, err = docker.GetFreePortStr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	notificationTestServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		msg, _ := io.ReadAll(request.Body)
+		t.Log("notification:", request.URL.String(), string(msg))
+	}))
+	defer notificationTestServer.Close()
+	config.NotificationUrl = notificationTestServer.URL
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	processId := ""
+
+	t.Run("deploy process", func(t *testing.T) {
+		processId, err = deployProcessWithInfo(config, "test", resources.ScriptErrParamsChangedBpmn, resources.SvgExample, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("set incident handler", func(t *testing.T) {
+		err, _ = client.New("http://localhost:"+config.ApiPort).SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId:        processId,
+			Restart:                    true,
+			RestartWithStartParameters: true,
+			Notify:                     true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("start process", func(t *testing.T) {
+		c, err := camunda.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = c.StartProcessWithVariables(processId, "", map[string]messages.CamundaVariable{"foo": {Value: "bar", Type: "String"}}, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	time.Sleep(1 * time.Minute)
+
+	t.Run("check restarted instances", func(t *testing.T) {
+		s, err := shards.New(config.ShardsDb, cache.None)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		shard, err := s.EnsureShardForUser("testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp, err := http.Get(shard + "/engine-rest/history/detail?variableUpdates=true&variableName=foo&processDefinitionId=" + url.QueryEscape(processId))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		updates := []struct {
+			ProcessInstanceId string      `json:"processInstanceId"`
+			Revision          int64       `json:"revision"`
+			Value             interface{} `json:"value"`
+		}{}
+		err = json.NewDecoder(resp.Body).Decode(&updates)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		//every instance is started with foo=bar and changes it to foo=changed
+		startValues := map[string]interface{}{}
+		startRevisions := map[string]int64{}
+		changed := 0
+		for _, update := range updates {
+			if update.Value == "changed" {
+				changed++
+			}
+			if revision, ok := startRevisions[update.ProcessInstanceId]; ok && revision <= update.Revision {
+				continue
+			}
+			startRevisions[update.ProcessInstanceId] = update.Revision
+			startValues[update.ProcessInstanceId] = update.Value
+		}
+		t.Log("log: instance count =", len(startValues))
+		if len(startValues) < 2 {
+			t.Error("expected at least one restarted instance with start parameters")
+		}
+		if changed < len(startValues) {
+			t.Error("expected changed variables", updates)
+		}
+		for instance, value := range startValues {
+			if value != "bar" {
+				t.Error(instance, value)
+			}
+		}
+	})
+}
+
+func TestScriptIncidentRetry(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	defaultConfig.MetricsPort, err = docker.GetFreePortStr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	notificationTestServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		msg, _ := io.ReadAll(request.Body)
+		t.Log("notification:", request.URL.String(), string(msg))
+	}))
+	defer notificationTestServer.Close()
+	config.NotificationUrl = notificationTestServer.URL
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	processId := ""
+
+	t.Run("deploy process", func(t *testing.T) {
+		processId, err = deployProcessWithInfo(config, "test", resources.ScriptErrBpmn, resources.SvgExample, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("set incident handler", func(t *testing.T) {
+		err, _ = client.New("http://localhost:"+config.ApiPort).SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: processId,
+			Restart:             true,
+			RestartMode:         messages.OnIncidentRestartModeRetry,
+			RestartRetries:      1,
+			Notify:              true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	processInstanceId := ""
+	t.Run("start process", func(t *testing.T) {
+		c, err := camunda.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = c.StartProcess(processId, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	time.Sleep(1 * time.Minute)
+
+	s, err := shards.New(config.ShardsDb, cache.None)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	shard, err := s.EnsureShardForUser("testuser")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("check process instance is kept", func(t *testing.T) {
+		resp, err := http.Get(shard + "/engine-rest/process-instance?processDefinitionId=" + processId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		instances := []messages.HistoricProcessInstance{}
+		err = json.NewDecoder(resp.Body).Decode(&instances)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(instances) != 1 {
+			t.Error("expected exactly one running process instance", instances)
+			return
+		}
+		processInstanceId = instances[0].Id
+	})
+
+	t.Run("check retry", func(t *testing.T) {
+		resp, err := http.Get(shard + "/engine-rest/history/job-log/count?failureLog=true&processInstanceId=" + processInstanceId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		count := struct {
+			Count int `json:"count"`
+		}{}
+		err = json.NewDecoder(resp.Body).Decode(&count)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		//3 default retries of camunda + 1 retry by the incident handler
+		if count.Count < 4 {
+			t.Error("expected at least 4 failed job executions", count.Count)
+		}
+	})
+}
+
+func TestScriptIncidentKeepAlive(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	defaultConfig.MetricsPort, err = docker.GetFreePortStr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mux := sync.Mutex{}
+	notificationCount := 0
+	notificationTestServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		msg, _ := io.ReadAll(request.Body)
+		t.Log("notification:", request.URL.String(), string(msg))
+		mux.Lock()
+		defer mux.Unlock()
+		notificationCount = notificationCount + 1
+	}))
+	defer notificationTestServer.Close()
+	config.NotificationUrl = notificationTestServer.URL
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	processId := ""
+
+	t.Run("deploy process", func(t *testing.T) {
+		processId, err = deployProcessWithInfo(config, "test", resources.ScriptErrBpmn, resources.SvgExample, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("set incident handler", func(t *testing.T) {
+		err, _ = client.New("http://localhost:"+config.ApiPort).SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: processId,
+			KeepAlive:           true,
+			Notify:              true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("start process", func(t *testing.T) {
+		c, err := camunda.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = c.StartProcess(processId, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	time.Sleep(1 * time.Minute)
+
+	t.Run("check process instance is kept", func(t *testing.T) {
+		s, err := shards.New(config.ShardsDb, cache.None)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		shard, err := s.EnsureShardForUser("testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp, err := http.Get(shard + "/engine-rest/process-instance?processDefinitionId=" + processId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		instances := []messages.HistoricProcessInstance{}
+		err = json.NewDecoder(resp.Body).Decode(&instances)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(instances) != 1 {
+			t.Error("expected exactly one running process instance", instances)
+		}
+	})
+
+	t.Run("check database", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoUrl))
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		count, err := client.Database(config.MongoDatabaseName).Collection(config.MongoIncidentCollectionName).CountDocuments(ctx, bson.M{})
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		if count != 1 {
+			t.Error("expected exactly one incident", count)
+		}
+	})
+
+	t.Run("check notifications", func(t *testing.T) {
+		mux.Lock()
+		defer mux.Unlock()
+		if notificationCount != 1 {
+			t.Error("expected exactly one notification", notificationCount)
+		}
+	})
+}
+
+func TestScriptIncidentWithUnavailableShard(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	defaultConfig.MetricsPort, err = docker.GetFreePortStr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("add unavailable shard", func(t *testing.T) {
+		s, err := shards.New(config.ShardsDb, cache.None)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		//ensure the user is assigned to the working shard
+		_, err = s.EnsureShardForUser("testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = s.EnsureShard("http://localhost:1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	processId := ""
+
+	t.Run("deploy process", func(t *testing.T) {
+		processId, err = deployProcessWithInfo(config, "test", resources.ScriptErrBpmn, resources.SvgExample, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("start process", func(t *testing.T) {
+		c, err := camunda.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = c.StartProcess(processId, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	time.Sleep(1 * time.Minute)
+
+	t.Run("check database", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoUrl))
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		count, err := client.Database(config.MongoDatabaseName).Collection(config.MongoIncidentCollectionName).CountDocuments(ctx, bson.M{"process_definition_id": processId})
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		if count == 0 {
+			t.Error("expected incidents of the available shard to be handled")
+		}
+	})
+
+	t.Run("check metrics", func(t *testing.T) {
+		resp, err := http.Get("http://localhost:" + config.MetricsPort + "/metrics")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		metrics, _ := io.ReadAll(resp.Body)
+		if !strings.Contains(string(metrics), `incident_worker_camunda_shard_polls{result="error",shard="http://localhost:1"}`) {
+			t.Error("expected error metric for unavailable shard")
+		}
+	})
+}
+
+func TestScriptIncidentBackfill(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	defaultConfig.MetricsPort, err = docker.GetFreePortStr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	processId := ""
+
+	t.Run("deploy process", func(t *testing.T) {
+		processId, err = deployProcessWithInfo(config, "test", resources.ScriptErrBpmn, resources.SvgExample, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("start process", func(t *testing.T) {
+		c, err := camunda.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = c.StartProcess(processId, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	time.Sleep(1 * time.Minute)
+
+	t.Run("remove stored incidents", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoUrl))
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		result, err := client.Database(config.MongoDatabaseName).Collection(config.MongoIncidentCollectionName).DeleteMany(ctx, bson.M{"process_definition_id": processId})
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		if result.DeletedCount == 0 {
+			t.Error("expected stored incidents")
+		}
+	})
+
+	c := client.New("http://localhost:" + config.ApiPort)
+
+	waitForBackfill := func(t *testing.T, id string) messages.BackfillJob {
+		for i := 0; i < 30; i++ {
+			job, err, _ := c.GetBackfill(client.InternalAdminToken, id)
+			if err != nil {
+				t.Error(err)
+				return job
+			}
+			if job.Status != messages.BackfillStatusRunning {
+				return job
+			}
+			time.Sleep(time.Second)
+		}
+		t.Error("backfill did not finish")
+		return messages.BackfillJob{}
+	}
+
+	t.Run("backfill as non admin", func(t *testing.T) {
+		_, err, code := c.StartBackfill(OtherUserToken, messages.BackfillRequest{})
+		if err == nil || code != http.StatusForbidden {
+			t.Error(err, code)
+		}
+	})
+
+	t.Run("backfill", func(t *testing.T) {
+		job, err, _ := c.StartBackfill(client.InternalAdminToken, messages.BackfillRequest{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		job = waitForBackfill(t, job.Id)
+		if job.Status != messages.BackfillStatusFinished {
+			t.Error(job.Status, job.Errors)
+			return
+		}
+		if job.Inserted == 0 || job.Skipped != 0 || job.Invalid != 0 || job.Updated.IsZero() {
+			t.Errorf("%#v", job)
+		}
+	})
+
+	t.Run("check backfilled incidents", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoUrl))
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		cursor, err := client.Database(config.MongoDatabaseName).Collection(config.MongoIncidentCollectionName).Find(ctx, bson.M{"process_definition_id": processId})
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		incidents := []messages.Incident{}
+		err = cursor.All(ctx, &incidents)
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		if len(incidents) == 0 {
+			t.Error("expected backfilled incidents")
+			return
+		}
+		for _, incident := range incidents {
+			if incident.TenantId != "testuser" || incident.DeploymentName != "test" || incident.ErrorMessage == "" {
+				t.Errorf("%#v", incident)
+			}
+		}
+	})
+
+	t.Run("repeat backfill", func(t *testing.T) {
+		job, err, _ := c.StartBackfill(client.InternalAdminToken, messages.BackfillRequest{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		job = waitForBackfill(t, job.Id)
+		if job.Status != messages.BackfillStatusFinished {
+			t.Error(job.Status, job.Errors)
+			return
+		}
+		if job.Inserted != 0 || job.Skipped == 0 {
+			t.Errorf("%#v", job)
+		}
+	})
+
+	t.Run("list backfills", func(t *testing.T) {
+		jobs, err, _ := c.ListBackfills(client.InternalAdminToken)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(jobs) != 2 {
+			t.Errorf("%#v", jobs)
+		}
+	})
+
+	t.Run("check stored backfills", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoUrl))
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		count, err := client.Database(config.MongoDatabaseName).Collection(config.MongoBackfillCollectionName).CountDocuments(ctx, bson.M{"expires_at": bson.M{"$gt": time.Now()}})
+		if err != nil {
+			t.Errorf("ERROR: %+v", err)
+			return
+		}
+		if count != 2 {
+			t.Error(count)
+		}
+	})
+
+	t.Run("stale backfill is failed", func(t *testing.T) {
+		db, err := database.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = db.SaveBackfillJob(messages.BackfillJob{
+			Id:      "stale",
+			Status:  messages.BackfillStatusRunning,
+			Started: time.Now().Add(-time.Hour),
+			Updated: time.Now().Add(-time.Hour),
+			Errors:  map[string]string{},
+		}, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		job, err, _ := c.GetBackfill(client.InternalAdminToken, "stale")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if job.Status != messages.BackfillStatusFailed || len(job.Errors) != 1 || job.Finished.IsZero() {
+			t.Errorf("%#v", job)
+		}
+	})
+}
+
+func TestScriptIncidentBackfillOpenIncident(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+	//without poller, the incident stays open in camunda and is only found by the backfill
+	defaultConfig.CamundaIncidentRequestInterval = "-"
+
+	defaultConfig.MetricsPort, err = docker.GetFreePortStr()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	processId := ""
+
+	t.Run("deploy process", func(t *testing.T) {
+		processId, err = deployProcessWithInfo(config, "test", resources.ScriptErrBpmn, resources.SvgExample, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("start process", func(t *testing.T) {
+		c, err := camunda.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = c.StartProcess(processId, "testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	time.Sleep(10 * time.Second)
+
+	c := client.New("http://localhost:" + config.ApiPort)
+
+	t.Run("backfill", func(t *testing.T) {
+		job, err, _ := c.StartBackfill(client.InternalAdminToken, messages.BackfillRequest{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for i := 0; i < 30 && job.Status == messages.BackfillStatusRunning; i++ {
+			time.Sleep(time.Second)
+			job, err, _ = c.GetBackfill(client.InternalAdminToken, job.Id)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		if job.Status != messages.BackfillStatusFinished || job.Inserted == 0 {
+			t.Errorf("%#v", job)
+		}
+	})
+
+	t.Run("open incident is handled", func(t *testing.T) {
+		s, err := shards.New(config.ShardsDb, cache.None)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		shard, err := s.EnsureShardForUser("testuser")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp, err := http.Get(shard + "/engine-rest/process-instance/count?processDefinitionId=" + url.QueryEscape(processId))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		count := struct {
+			Count int64 `json:"count"`
+		}{}
+		err = json.NewDecoder(resp.Body).Decode(&count)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if count.Count != 0 {
+			t.Error("expected stopped process instance", count.Count)
+		}
+	})
 }
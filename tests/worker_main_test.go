@@ -23,9 +23,16 @@ import (
 	"github.com/SENERGY-Platform/process-incident-api/lib/camunda"
 	"github.com/SENERGY-Platform/process-incident-api/lib/client"
 	"github.com/SENERGY-Platform/process-incident-api/lib/configuration"
+	"github.com/SENERGY-Platform/process-incident-api/lib/controller"
 	"github.com/SENERGY-Platform/process-incident-api/lib/database"
 	"github.com/SENERGY-Platform/process-incident-api/lib/messages"
+	"github.com/SENERGY-Platform/process-incident-api/lib/metrics"
 	"github.com/SENERGY-Platform/process-incident-api/tests/server"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"net/http"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -67,6 +74,7 @@ func TestDatabaseDeprecated(t *testing.T) {
 		Time:                time.Now(),
 		DeploymentName:      "pdid",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 
 	t.Run("send incident", func(t *testing.T) {
@@ -120,6 +128,7 @@ func TestDatabase(t *testing.T) {
 		Time:                time.Now(),
 		DeploymentName:      "pdid",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 
 	t.Run("send incident", func(t *testing.T) {
@@ -188,6 +197,7 @@ func TestCamunda(t *testing.T) {
 		ErrorMessage:        "error message",
 		Time:                time.Now(),
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 
 	t.Run("send incident", func(t *testing.T) {
@@ -262,6 +272,7 @@ func TestCamundaDeprecated(t *testing.T) {
 		ErrorMessage:        "error message",
 		Time:                time.Now(),
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 
 	t.Run("send incident", func(t *testing.T) {
@@ -320,6 +331,7 @@ func TestDeleteByDeploymentIdDeprecated(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid1",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident12 := messages.Incident{
 		Id:                  "b",
@@ -332,6 +344,7 @@ func TestDeleteByDeploymentIdDeprecated(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid2",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident21 := messages.Incident{
 		Id:                  "c",
@@ -344,6 +357,7 @@ func TestDeleteByDeploymentIdDeprecated(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid1",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident22 := messages.Incident{
 		Id:                  "d",
@@ -356,6 +370,7 @@ func TestDeleteByDeploymentIdDeprecated(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid2",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 
 	t.Run("send incidents", func(t *testing.T) {
@@ -457,6 +472,7 @@ func TestDeleteByDeploymentId(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid1",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident12 := messages.Incident{
 		MsgVersion:          3,
@@ -469,6 +485,7 @@ func TestDeleteByDeploymentId(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid2",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident21 := messages.Incident{
 		MsgVersion:          3,
@@ -481,6 +498,7 @@ func TestDeleteByDeploymentId(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid1",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident22 := messages.Incident{
 		MsgVersion:          3,
@@ -493,6 +511,7 @@ func TestDeleteByDeploymentId(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid2",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 
 	t.Run("send incidents", func(t *testing.T) {
@@ -586,6 +605,7 @@ func TestDeleteByInstanceIdDeprecated(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid1",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident12 := messages.Incident{
 		Id:                  "b",
@@ -598,6 +618,7 @@ func TestDeleteByInstanceIdDeprecated(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid2",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident21 := messages.Incident{
 		Id:                  "c",
@@ -610,6 +631,7 @@ func TestDeleteByInstanceIdDeprecated(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid1",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident22 := messages.Incident{
 		Id:                  "d",
@@ -622,6 +644,7 @@ func TestDeleteByInstanceIdDeprecated(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid2",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 
 	t.Run("send incidents", func(t *testing.T) {
@@ -703,6 +726,7 @@ func TestDeleteByInstanceId(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid1",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident12 := messages.Incident{
 		MsgVersion:          3,
@@ -715,6 +739,7 @@ func TestDeleteByInstanceId(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid2",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident21 := messages.Incident{
 		MsgVersion:          3,
@@ -727,6 +752,7 @@ func TestDeleteByInstanceId(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid1",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 	incident22 := messages.Incident{
 		MsgVersion:          3,
@@ -739,6 +765,7 @@ func TestDeleteByInstanceId(t *testing.T) {
 		Time:                time.Time{},
 		DeploymentName:      "pdid2",
 		TenantId:            UserId,
+		Severity:            messages.SeverityMedium,
 	}
 
 	t.Run("send incidents", func(t *testing.T) {
@@ -787,3 +814,811 @@ func TestDeleteByInstanceId(t *testing.T) {
 		checkIncidentsInDatabase(t, config, incident21, incident22)
 	})
 }
+
+func TestOnIncidentHandler(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	c := client.New("http://localhost:" + config.ApiPort)
+
+	handler1 := messages.OnIncident{
+		ProcessDefinitionId: "pdid1",
+		Restart:             true,
+		Notify:              false,
+	}
+	handler2 := messages.OnIncident{
+		ProcessDefinitionId: "pdid2",
+		Restart:             false,
+		Notify:              true,
+	}
+
+	t.Run("set handlers", func(t *testing.T) {
+		err, _ = c.SetOnIncidentHandler(client.InternalAdminToken, handler1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err, _ = c.SetOnIncidentHandler(client.InternalAdminToken, handler2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("get handler", func(t *testing.T) {
+		handler, err, _ := c.GetOnIncidentHandler(client.InternalAdminToken, "pdid1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(handler, handler1) {
+			t.Error(handler, handler1)
+		}
+	})
+
+	t.Run("list handlers", func(t *testing.T) {
+		handlers, err, _ := c.ListOnIncidentHandlers(client.InternalAdminToken, 10, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(handlers, []messages.OnIncident{handler1, handler2}) {
+			t.Error(handlers)
+		}
+	})
+
+	t.Run("delete handler", func(t *testing.T) {
+		err, _ = c.DeleteOnIncidentHandler(client.InternalAdminToken, "pdid1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err, code := c.GetOnIncidentHandler(client.InternalAdminToken, "pdid1")
+		if err == nil || code != http.StatusNotFound {
+			t.Error(err, code)
+		}
+		checkOnIncidentsInDatabase(t, config, handler2)
+	})
+}
+
+func TestTenantOnIncidentHandler(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	definitionId := ""
+	t.Run("deploy process", func(t *testing.T) {
+		definitionId, err = deployProcessWithInfo(config, "test", xml, "<svg/>", UserId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	c := client.New("http://localhost:" + config.ApiPort)
+
+	t.Run("set handler as owner", func(t *testing.T) {
+		err, _ = c.SetOnIncidentHandler(UserToken, messages.OnIncident{
+			ProcessDefinitionId: definitionId,
+			Restart:             true,
+			Notify:              true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("set handler as other user", func(t *testing.T) {
+		err, code := c.SetOnIncidentHandler(OtherUserToken, messages.OnIncident{
+			ProcessDefinitionId: definitionId,
+			Restart:             false,
+			Notify:              false,
+		})
+		if err == nil || code != http.StatusForbidden {
+			t.Error(err, code)
+		}
+	})
+
+	expected := messages.OnIncident{
+		ProcessDefinitionId: definitionId,
+		Restart:             true,
+		Notify:              true,
+		TenantId:            UserId,
+	}
+
+	t.Run("get handler as owner", func(t *testing.T) {
+		handler, err, _ := c.GetOnIncidentHandler(UserToken, definitionId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(handler, expected) {
+			t.Error(handler, expected)
+		}
+	})
+
+	t.Run("list handlers", func(t *testing.T) {
+		handlers, err, _ := c.ListOnIncidentHandlers(UserToken, 10, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(handlers, []messages.OnIncident{expected}) {
+			t.Error(handlers)
+		}
+		handlers, err, _ = c.ListOnIncidentHandlers(OtherUserToken, 10, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(handlers) != 0 {
+			t.Error(handlers)
+		}
+	})
+
+	t.Run("set handler as admin without tenant", func(t *testing.T) {
+		err, _ = c.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: definitionId,
+			Restart:             false,
+			Notify:              true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expected.Restart = false
+		handlers, err, _ := c.ListOnIncidentHandlers(UserToken, 10, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(handlers, []messages.OnIncident{expected}) {
+			t.Error(handlers)
+		}
+	})
+
+	t.Run("delete handler as other user", func(t *testing.T) {
+		err, code := c.DeleteOnIncidentHandler(OtherUserToken, definitionId)
+		if err == nil || code != http.StatusForbidden {
+			t.Error(err, code)
+		}
+	})
+
+	t.Run("delete handler as owner", func(t *testing.T) {
+		err, _ = c.DeleteOnIncidentHandler(UserToken, definitionId)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		checkOnIncidentsInDatabase(t, config, []messages.OnIncident{}...)
+	})
+}
+
+func TestRestartBudget(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	c := client.New("http://localhost:" + config.ApiPort)
+
+	t.Run("set invalid handler", func(t *testing.T) {
+		err, code := c.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: "pdid1",
+			Restart:             true,
+			RestartWindow:       "foo",
+		})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+	})
+
+	t.Run("set handler", func(t *testing.T) {
+		err, _ = c.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: "pdid1",
+			Restart:             true,
+			MaxRestarts:         2,
+			RestartWindow:       "1h",
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("send incidents", func(t *testing.T) {
+		for _, id := range []string{"a", "b", "c"} {
+			err, _ = c.CreateIncident(client.InternalAdminToken, messages.Incident{
+				MsgVersion:          3,
+				Id:                  id,
+				ExternalTaskId:      "task_id",
+				ProcessInstanceId:   "piid_" + id,
+				ProcessDefinitionId: "pdid1",
+				WorkerId:            "w",
+				ErrorMessage:        "error message",
+				Time:                time.Now(),
+				TenantId:            UserId,
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	})
+
+	t.Run("check handler", func(t *testing.T) {
+		handler, err, _ := c.GetOnIncidentHandler(client.InternalAdminToken, "pdid1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if handler.Restart {
+			t.Error("expected restart to be disabled", handler)
+		}
+		if handler.RestartCount != 3 {
+			t.Error(handler.RestartCount)
+		}
+	})
+}
+
+func TestPendingRestart(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	//notifications stay in the outbox because no dispatcher is started
+	config.NotificationUrl = "http://localhost:1"
+
+	camundaInstance, err := camunda.Factory.Get(ctx, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	db, err := database.Factory.Get(ctx, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mongoCtx, mongoCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer mongoCancel()
+	mongoClient, err := mongo.Connect(mongoCtx, options.Client().ApplyURI(config.MongoUrl))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	countPendingRestarts := func(t *testing.T) int64 {
+		count, err := mongoClient.Database(config.MongoDatabaseName).Collection(config.MongoPendingRestartCollectionName).CountDocuments(mongoCtx, bson.M{})
+		if err != nil {
+			t.Error(err)
+		}
+		return count
+	}
+	countRestartNotifications := func(t *testing.T) int64 {
+		count, err := mongoClient.Database(config.MongoDatabaseName).Collection(config.MongoOutboxCollectionName).CountDocuments(mongoCtx, bson.M{"title": bson.M{"$regex": "^ERROR: unable to restart process"}, "channel_id": bson.M{"$exists": false}})
+		if err != nil {
+			t.Error(err)
+		}
+		return count
+	}
+	countRestartChannelNotifications := func(t *testing.T) int64 {
+		count, err := mongoClient.Database(config.MongoDatabaseName).Collection(config.MongoOutboxCollectionName).CountDocuments(mongoCtx, bson.M{"title": bson.M{"$regex": "^ERROR: unable to restart process"}, "channel_id": "restart_channel"})
+		if err != nil {
+			t.Error(err)
+		}
+		return count
+	}
+
+	err = db.SaveNotificationChannel(messages.NotificationChannel{
+		Id:                  "restart_channel",
+		TenantId:            UserId,
+		ProcessDefinitionId: "pdid1",
+		Type:                messages.NotificationChannelTypeWebhook,
+		Url:                 "https://hooks.example.com/hook",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("schedule restart on stopping instance", func(t *testing.T) {
+		instanceCtx, instanceCancel := context.WithCancel(ctx)
+		defer instanceCancel()
+		ctrl, err := controller.New(instanceCtx, config, db, camundaInstance, metrics.New())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err, _ = ctrl.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: "pdid1",
+			Restart:             true,
+			Notify:              false,
+			RestartBackoff:      "2s",
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err, _ = ctrl.CreateIncident(client.InternalAdminToken, messages.Incident{
+			MsgVersion:          3,
+			Id:                  "a",
+			ExternalTaskId:      "task_id",
+			ProcessInstanceId:   "piid_a",
+			ProcessDefinitionId: "pdid1",
+			WorkerId:            "w",
+			ErrorMessage:        "error message",
+			Time:                time.Now(),
+			TenantId:            UserId,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("check pending restart", func(t *testing.T) {
+		if count := countPendingRestarts(t); count != 1 {
+			t.Error(count)
+		}
+		if count := countRestartNotifications(t); count != 0 {
+			t.Error(count)
+		}
+	})
+
+	t.Run("restart is not run before due", func(t *testing.T) {
+		ctrl, err := controller.New(ctx, config, db, camundaInstance, metrics.New())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		ctrl.RunDueRestarts()
+		if count := countPendingRestarts(t); count != 1 {
+			t.Error(count)
+		}
+	})
+
+	t.Run("run restart on other instance", func(t *testing.T) {
+		time.Sleep(3 * time.Second)
+		ctrl, err := controller.New(ctx, config, db, camundaInstance, metrics.New())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		ctrl.RunDueRestarts()
+		if count := countPendingRestarts(t); count != 0 {
+			t.Error(count)
+		}
+		//pdid1 is not deployed, so the restart fails and the user is notified
+		if count := countRestartNotifications(t); count != 1 {
+			t.Error(count)
+		}
+		if count := countRestartChannelNotifications(t); count != 1 {
+			t.Error(count)
+		}
+	})
+
+	t.Run("drop restart of disabled handler", func(t *testing.T) {
+		ctrl, err := controller.New(ctx, config, db, camundaInstance, metrics.New())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err, _ = ctrl.CreateIncident(client.InternalAdminToken, messages.Incident{
+			MsgVersion:          3,
+			Id:                  "b",
+			ExternalTaskId:      "task_id",
+			ProcessInstanceId:   "piid_b",
+			ProcessDefinitionId: "pdid1",
+			WorkerId:            "w",
+			ErrorMessage:        "error message",
+			Time:                time.Now(),
+			TenantId:            UserId,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if count := countPendingRestarts(t); count != 1 {
+			t.Error(count)
+		}
+		err, _ = ctrl.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: "pdid1",
+			Restart:             false,
+			Notify:              false,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		time.Sleep(3 * time.Second)
+		ctrl.RunDueRestarts()
+		if count := countPendingRestarts(t); count != 0 {
+			t.Error(count)
+		}
+		//the restart is dropped without running it, so no additional failure is notified
+		if count := countRestartNotifications(t); count != 1 {
+			t.Error(count)
+		}
+	})
+}
+
+func TestDeduplication(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	c := client.New("http://localhost:" + config.ApiPort)
+
+	t.Run("set handlers", func(t *testing.T) {
+		err, code := c.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: "pdid1",
+			DeduplicationWindow: "foo",
+		})
+		if err == nil || code != http.StatusBadRequest {
+			t.Error(err, code)
+		}
+		err, _ = c.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: "pdid1",
+			DeduplicationWindow: "1m",
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err, _ = c.SetOnIncidentHandler(client.InternalAdminToken, messages.OnIncident{
+			ProcessDefinitionId: "pdid2",
+			DeduplicationWindow: "0s",
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("send incidents", func(t *testing.T) {
+		for _, incident := range []messages.Incident{
+			{Id: "a", ProcessDefinitionId: "pdid1"},
+			{Id: "b", ProcessDefinitionId: "pdid1"},
+			{Id: "a", ProcessDefinitionId: "pdid1"},
+			{Id: "c", ProcessDefinitionId: "pdid2"},
+			{Id: "d", ProcessDefinitionId: "pdid2"},
+		} {
+			incident.MsgVersion = 3
+			incident.ProcessInstanceId = "piid1"
+			incident.ErrorMessage = "error message"
+			incident.Time = time.Now()
+			incident.TenantId = UserId
+			err, _ = c.CreateIncident(client.InternalAdminToken, incident)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	})
+
+	t.Run("check deduplicated incident", func(t *testing.T) {
+		incident, err, _ := c.GetIncident(UserToken, "a")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if incident.OccurrenceCount != 2 || incident.LastSeen.IsZero() {
+			t.Error(incident.OccurrenceCount, incident.LastSeen)
+		}
+		_, err, code := c.GetIncident(UserToken, "b")
+		if err == nil || code != http.StatusNotFound {
+			t.Error(err, code)
+		}
+	})
+
+	t.Run("check incidents without deduplication", func(t *testing.T) {
+		for _, id := range []string{"c", "d"} {
+			incident, err, _ := c.GetIncident(UserToken, id)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if incident.OccurrenceCount != 0 {
+				t.Error(incident.OccurrenceCount)
+			}
+		}
+	})
+
+	t.Run("repeated report keeps status", func(t *testing.T) {
+		err, _ := c.SetIncidentStatus(UserToken, "c", messages.IncidentStatusAcknowledged)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err, _ = c.CreateIncident(client.InternalAdminToken, messages.Incident{
+			Id:                  "c",
+			MsgVersion:          3,
+			ProcessDefinitionId: "pdid2",
+			ProcessInstanceId:   "piid1",
+			ErrorMessage:        "changed error message",
+			Time:                time.Now(),
+			TenantId:            UserId,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		incident, err, _ := c.GetIncident(UserToken, "c")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if incident.Status != messages.IncidentStatusAcknowledged || incident.StatusChangedAt.IsZero() || incident.ErrorMessage != "changed error message" {
+			t.Errorf("%#v", incident)
+		}
+	})
+}
+
+func TestSharedDeduplication(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+	defaultConfig.SharedIncidentDeduplication = true
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	//two controllers simulate two replicas of the worker
+	replicas := []*controller.Controller{}
+	for i := 0; i < 2; i++ {
+		camundaInstance, err := camunda.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		databaseInstance, err := database.Factory.Get(ctx, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		ctrl, err := controller.New(ctx, config, databaseInstance, camundaInstance, metrics.New())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		replicas = append(replicas, ctrl)
+	}
+
+	incident := messages.Incident{
+		MsgVersion:          3,
+		Id:                  "a",
+		ExternalTaskId:      "task_id",
+		ProcessInstanceId:   "piid1",
+		ProcessDefinitionId: "pdid1",
+		WorkerId:            "w",
+		ErrorMessage:        "error message",
+		TenantId:            UserId,
+	}
+
+	t.Run("send incidents to both replicas", func(t *testing.T) {
+		err, _ = replicas[0].CreateIncident(client.InternalAdminToken, incident)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		duplicate := incident
+		duplicate.Id = "b"
+		err, _ = replicas[1].CreateIncident(client.InternalAdminToken, duplicate)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("check database", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.MongoUrl))
+		if err != nil {
+			t.Fatalf("ERROR: %+v", err)
+			return
+		}
+		incidents := client.Database(config.MongoDatabaseName).Collection(config.MongoIncidentCollectionName)
+		count, err := incidents.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if count != 1 {
+			t.Error("expected exactly one incident", count)
+			return
+		}
+		stored := messages.Incident{}
+		err = incidents.FindOne(ctx, bson.M{"id": "a"}).Decode(&stored)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if stored.OccurrenceCount != 2 {
+			t.Error(stored.OccurrenceCount)
+		}
+	})
+}
+
+func TestIncidentDetails(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defaultConfig, err := configuration.LoadConfig("../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defaultConfig.Debug = true
+
+	config, err := server.New(ctx, wg, defaultConfig)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	err = lib.StartWith(ctx, config, api.Factory, database.Factory, camunda.Factory)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	c := client.New("http://localhost:" + config.ApiPort)
+
+	t.Run("send incident", func(t *testing.T) {
+		err, _ = c.CreateIncident(client.InternalAdminToken, messages.Incident{
+			MsgVersion:          3,
+			Id:                  "a",
+			ProcessInstanceId:   "piid1",
+			ProcessDefinitionId: "pdid1",
+			ErrorMessage:        "error message",
+			Time:                time.Now(),
+			TenantId:            UserId,
+			StackTrace:          "stack trace",
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	})
+
+	t.Run("get without details", func(t *testing.T) {
+		incident, err, _ := c.GetIncident(UserToken, "a")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if incident.StackTrace != "" {
+			t.Error(incident.StackTrace)
+		}
+	})
+
+	t.Run("get with details", func(t *testing.T) {
+		incident, err, _ := c.GetIncidentDetails(UserToken, "a")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if incident.StackTrace != "stack trace" {
+			t.Error(incident.StackTrace)
+		}
+	})
+
+	t.Run("list without details", func(t *testing.T) {
+		incidents, _, err, _ := c.FindIncidents(UserToken, messages.FindIncidentsOptions{Limit: 10, SortBy: "id", Asc: true})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(incidents) != 1 || incidents[0].StackTrace != "" {
+			t.Error(incidents)
+		}
+	})
+}